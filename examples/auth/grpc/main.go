@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/xiaohangshu-dev/go-workit/internal/service1/grpcapi/hello"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/authz"
+)
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddAuthentication(func(options *auth.Options) {
+
+		options.DefaultScheme = "local_jwt_bearer"
+
+		// 从 authorization 元数据中读取 Bearer token
+		options.AddJwtBearer("local_jwt_bearer", func(options *jwt.Options) {
+			options.TokenValidationParameters = jwt.TokenValidationParameters{
+				ValidateIssuer:           true,
+				ValidateAudience:         true,
+				ValidateLifetime:         true,
+				ValidateIssuerSigningKey: true,
+				SigningKey:               []byte("secret"),
+				ValidIssuer:              "sample",
+				ValidAudience:            "sample",
+				RequireExpiration:        true,
+			}
+		})
+
+		// 健康检查等方法允许匿名访问
+		options.AllowAnonymousGrpcMethod("/grpc.health.v1.Health/*")
+	})
+
+	builder.AddAuthorization(func(options *authz.Options) {
+
+		options.RequireRole("admin", "admin")
+
+		// 按完整方法名配置授权策略, 支持通配符
+		options.MapGrpcMethod("/hello.HelloService/*", "admin")
+	})
+
+	app := builder.Build()
+
+	// 未通过鉴权返回 Unauthenticated, 未通过授权返回 PermissionDenied
+	app.UseGrpcAuthentication()
+
+	app.UseGrpcAuthorization()
+
+	app.MapGrpcServices(hello.NewHelloService)
+
+	app.Run()
+}
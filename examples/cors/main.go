@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/cors"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+var secret = []byte("secret")
+
+// tenantOrigins 按租户白名单校验来源, 租户为请求域名的第一级, 如 acme.api.example.com 的租户为 acme
+// 实际项目中白名单通常来自数据库或配置中心
+type tenantOrigins struct {
+	allowlist map[string][]string
+	logger    *zap.Logger
+}
+
+func newTenantOrigins(logger *zap.Logger) *tenantOrigins {
+	return &tenantOrigins{
+		allowlist: map[string][]string{
+			"acme":   {"https://acme.com", "https://app.acme.com"},
+			"globex": {"https://globex.io"},
+		},
+		logger: logger,
+	}
+}
+
+// ValidateOrigin 实现 web.CorsOriginValidator
+func (t *tenantOrigins) ValidateOrigin(r *http.Request, policy, origin string) (bool, error) {
+	tenant, _, _ := strings.Cut(r.Host, ".")
+	for _, allowed := range t.allowlist[tenant] {
+		if strings.EqualFold(allowed, origin) {
+			return true, nil
+		}
+	}
+	t.logger.Info("origin not in tenant allowlist", zap.String("tenant", tenant), zap.String("origin", origin))
+	return false, nil
+}
+
+type LoginReq struct {
+	User string `json:"user" validate:"required"`
+}
+
+type LoginResp struct {
+	Token string `json:"token"`
+}
+
+func login(ctx context.Context, req LoginReq) (LoginResp, error) {
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{
+		"iss": "sample",
+		"aud": "sample",
+		"sub": req.User,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	return LoginResp{Token: token}, err
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddProblemDetails()
+
+	builder.AddAuthentication(func(options *auth.Options) {
+		options.DefaultScheme = "local_jwt_bearer"
+		options.AddJwtBearer("local_jwt_bearer", func(options *jwt.Options) {
+			options.TokenValidationParameters = jwt.TokenValidationParameters{
+				ValidateIssuer:           true,
+				ValidateAudience:         true,
+				ValidateLifetime:         true,
+				ValidateIssuerSigningKey: true,
+				SigningKey:               secret,
+				ValidIssuer:              "sample",
+				ValidAudience:            "sample",
+				RequireExpiration:        true,
+			}
+		})
+	})
+
+	// 策略从 application.yaml 的 cors 节点加载, 代码中可追加或修改
+	builder.AddCors(func(options *cors.Options) {
+		options.AddPolicy("admin", func(policy *web.CorsPolicy) {
+			policy.AllowOriginFunc = func(origin string) bool {
+				return strings.HasPrefix(origin, "http://localhost:")
+			}
+		})
+		options.UseOriginValidator(newTenantOrigins)
+	})
+
+	app := builder.Build()
+
+	// 跨域中间件在鉴权之前注册, 预检请求不携带凭据, 直接响应
+	app.UseCorsPolicies()
+	app.UseExceptionHandler()
+	app.UseAuthentication()
+	app.UseAuthorization()
+
+	// 使用默认策略 public
+	public := app.MapGroup("/public").WithAllowAnonymous()
+	public.MapGet("/products", func(c *gin.Context) {
+		c.JSON(http.StatusOK, []string{"apple", "banana"})
+	})
+	public.MapPost("/login", web.Handle(login))
+
+	// 管理后台仅允许指定来源并携带凭据
+	admin := app.MapGroup("/admin").
+		WithAuthenticationScheme("local_jwt_bearer").
+		WithCors("admin")
+	admin.MapGet("/users", func(c *gin.Context) {
+		c.Header("X-Request-Id", "1")
+		c.JSON(http.StatusOK, []string{"alice", "bob"})
+	})
+	admin.MapDelete("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	// 租户接口的来源按租户白名单动态校验
+	app.MapGroup("/orders").WithAllowAnonymous().WithCors("tenant").
+		MapPost("", func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"id": 1})
+		})
+
+	app.Run()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/authz"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/proxy"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+var secret = []byte("secret")
+
+type LoginReq struct {
+	User string `json:"user" validate:"required"`
+	Role string `json:"role" validate:"required"`
+}
+
+type LoginResp struct {
+	Token string `json:"token"`
+}
+
+func login(ctx context.Context, req LoginReq) (LoginResp, error) {
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{
+		"iss":  "sample",
+		"aud":  "sample",
+		"sub":  req.User,
+		"role": req.Role,
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	return LoginResp{Token: token}, err
+}
+
+// upstream 模拟上游服务, 响应中回显收到的路径与网关添加的请求头
+func upstream(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Powered-By", "upstream")
+		_, _ = fmt.Fprintf(w, `{"upstream":%q,"path":%q,"gateway":%q,"forwardedFor":%q}`,
+			addr, r.URL.Path, r.Header.Get("X-Gateway"), r.Header.Get("X-Forwarded-For"))
+	})
+	go func() {
+		_ = http.ListenAndServe(addr, mux)
+	}()
+}
+
+func main() {
+
+	upstream("127.0.0.1:9101")
+	upstream("127.0.0.1:9102")
+
+	builder := webapp.NewBuilder()
+
+	builder.AddProblemDetails()
+
+	builder.AddAuthentication(func(options *auth.Options) {
+		options.DefaultScheme = "local_jwt_bearer"
+		options.AddJwtBearer("local_jwt_bearer", func(options *jwt.Options) {
+			options.TokenValidationParameters = jwt.TokenValidationParameters{
+				ValidateIssuer:           true,
+				ValidateAudience:         true,
+				ValidateLifetime:         true,
+				ValidateIssuerSigningKey: true,
+				SigningKey:               secret,
+				ValidIssuer:              "sample",
+				ValidAudience:            "sample",
+				RequireExpiration:        true,
+			}
+		})
+	})
+
+	builder.AddAuthorization(func(options *authz.Options) {
+		options.RequireRole("admin", "admin")
+	})
+
+	builder.AddRateLimiter(func(opts *ratelimit.Options) {
+		opts.AddFixedWindowLimiter("orders", func(opts *ratelimit.FixedWindowOptions) {
+			opts.PermitLimit = 100
+			opts.Window = time.Minute
+		})
+	})
+
+	// 集群与代理路由从 application.yaml 的 proxy 节点加载, 代码中可追加或修改集群
+	builder.AddReverseProxy(func(options *proxy.Options) {
+		options.AddCluster("admin", func(cluster *proxy.ClusterOptions) {
+			cluster.Destinations = []string{"http://127.0.0.1:9102"}
+		})
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseAuthentication()
+	app.UseAuthorization()
+	app.UseRateLimiter()
+	app.UseRouteDiagnostics()
+
+	app.MapGroup("/auth").WithAllowAnonymous().MapPost("/login", web.Handle(login))
+
+	// 代码中注册的代理路由, 分组的鉴权与授权同样作用于转发的请求
+	app.MapGroup("/api/admin").
+		WithAuthenticationScheme("local_jwt_bearer").
+		WithAuthorizationPolicy("admin").
+		MapProxy("admin", web.ProxyTransform{StripPrefix: true, AddPrefix: "/internal"})
+
+	app.Run()
+}
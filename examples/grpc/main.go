@@ -11,17 +11,66 @@
 package main
 
 import (
+	"context"
+	"time"
+
 	_ "github.com/xiaohangshu-dev/go-workit/api/service1/docs" // swagger 一定要有这行,指向你的文档地址
 	"github.com/xiaohangshu-dev/go-workit/internal/service1/grpcapi/hello"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/health"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/rpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 func main() {
 
 	builder := webapp.NewBuilder()
 
+	builder.AddRateLimiter(func(opts *ratelimit.Options) {
+		opts.AddTokenBucketLimiter("hello", func(opts *ratelimit.TokenBucketOptions) {
+			opts.TokenLimit = 10
+			opts.TokensPerPeriod = 10
+			opts.ReplenishmentPeriod = time.Second
+		})
+
+		// 按完整方法名匹配限流策略, 支持通配符
+		opts.MapGrpcMethod("/hello.HelloService/*", "hello")
+
+		// 按请求元数据分区, 缺省按客户端地址
+		opts.GrpcPartition = ratelimit.PartitionByMetadata("x-api-key")
+	})
+
+	builder.ConfigureGrpc(func(opts *rpc.GrpcOptions) {
+		opts.MaxRecvMsgSize = 8 << 20
+		opts.Keepalive = &keepalive.ServerParameters{
+			Time:    30 * time.Second,
+			Timeout: 5 * time.Second,
+		}
+
+		// 注册反射服务便于 grpcurl 等工具调试, 会公开全部服务定义, 生产环境不建议开启
+		opts.EnableReflection = true
+
+		// 内置拦截器, 按注册顺序执行
+		opts.UseRecovery().UseLogger().UseValidator()
+
+		// 以 HTTP/JSON 暴露服务: 优先使用 google.api.http 注解, 否则为 POST /{package.Service}/{Method}
+		opts.EnableTranscoding()
+	})
+
+	// 健康检查同时用于 /health 与 grpc.health.v1
+	builder.AddHealthChecks(func(opts *health.Options) {
+		opts.AddCheck("self", func(ctx context.Context) error {
+			return nil
+		})
+	})
+
 	app := builder.Build()
 
+	app.UseHealthCheck()
+
+	app.UseGrpcRateLimiter()
+
 	app.MapGrpcServices(hello.NewHelloService)
 
 	app.Run()
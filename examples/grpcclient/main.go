@@ -1,30 +1,50 @@
 package main
 
 import (
-	"context"
-	"log"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	pb "github.com/xiaohangshu-dev/go-workit/internal/service1/grpcapi/hello" // 替换为你的实际 import 路径
-	"google.golang.org/grpc"
+	"github.com/xiaohangshu-dev/go-workit/pkg/components/grpcx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/grpcctx"
+	"go.uber.org/fx"
 )
 
+type Clients struct {
+	fx.In
+
+	Hello pb.HelloServiceClient `name:"hello"`
+}
+
 func main() {
-	conn, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
-	if err != nil {
-		log.Fatalf("failed to connect: %v", err)
-	}
-	defer conn.Close()
 
-	client := pb.NewHelloServiceClient(conn)
+	builder := webapp.NewBuilder()
+
+	builder.AddGrpcClients(func(opts *grpcctx.Options) {
+
+		// 连接参数从配置 grpc_clients.hello 加载, 也可以在代码中覆盖
+		grpcctx.AddClient(opts, "hello", pb.NewHelloServiceClient, func(cfg *grpcx.Options) {
+			cfg.Timeout = 3 * time.Second
+		})
+	})
+
+	app := builder.Build()
+
+	app.MapRoute(func(router *gin.Engine, clients Clients) {
+		router.GET("/hello", func(c *gin.Context) {
+			// 透传调用方的 authorization 与链路追踪头
+			ctx := grpcx.WithForwardedHeaders(c.Request.Context(), c.Request.Header)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+			resp, err := clients.Hello.SayHello(ctx, &pb.HelloRequest{Name: c.Query("name")})
+			if err != nil {
+				c.JSON(502, gin.H{"error": err.Error()})
+				return
+			}
 
-	resp, err := client.SayHello(ctx, &pb.HelloRequest{Name: "ChatGPT"})
-	if err != nil {
-		log.Fatalf("error calling SayHello: %v", err)
-	}
+			c.JSON(200, gin.H{"message": resp.Message})
+		})
+	})
 
-	log.Printf("Response: %s", resp.Message)
+	app.Run()
 }
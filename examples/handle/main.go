@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type GetUserReq struct {
+	ID      int64  `path:"id" validate:"gt=0"`
+	Fields  string `query:"fields"`
+	TraceID string `header:"X-Request-Id"`
+}
+
+type UserResp struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateUserReq struct {
+	Name  string `json:"name" validate:"required,min=2,max=32"`
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age" validate:"gte=18"`
+}
+
+// CreatedUser 实现 web.StatusCoder 返回 201
+type CreatedUser struct {
+	UserResp
+}
+
+func (CreatedUser) StatusCode() int {
+	return http.StatusCreated
+}
+
+type DeleteUserReq struct {
+	ID int64 `path:"id" validate:"gt=0"`
+}
+
+func getUser(ctx context.Context, req GetUserReq) (*UserResp, error) {
+	if req.ID != 1 {
+		return nil, problem.NotFound("user not found")
+	}
+	return &UserResp{ID: req.ID, Name: "alice", Email: "alice@example.com", CreatedAt: time.Now()}, nil
+}
+
+func createUser(ctx context.Context, req CreateUserReq) (CreatedUser, error) {
+	return CreatedUser{UserResp{ID: 2, Name: req.Name, Email: req.Email, CreatedAt: time.Now()}}, nil
+}
+
+// deleteUser 返回 nil 时响应 204
+func deleteUser(ctx context.Context, req DeleteUserReq) (*struct{}, error) {
+	return nil, nil
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+
+	app.MapRoute(func(router *gin.Engine) {
+		router.GET("/users/:id", web.Handle(getUser))
+		router.POST("/users", web.Handle(createUser))
+		router.DELETE("/users/:id", web.Handle(deleteUser))
+	})
+
+	app.Run()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type Article struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ETag 以版本号作为 ETag, 响应写出 ETag 头并处理 If-None-Match
+func (a *Article) ETag() string {
+	return strconv.FormatInt(a.Version, 10)
+}
+
+// LastModified 响应写出 Last-Modified 头并处理 If-Modified-Since
+func (a *Article) LastModified() time.Time {
+	return a.UpdatedAt
+}
+
+type GetArticleReq struct {
+	ID int64 `path:"id" validate:"required,min=1"`
+}
+
+type UpdateArticleReq struct {
+	ID    int64  `path:"id" validate:"required,min=1"`
+	Title string `json:"title" validate:"required,max=64"`
+}
+
+var (
+	mu       sync.Mutex
+	articles = map[int64]*Article{
+		1: {ID: 1, Title: "hello", Version: 1, UpdatedAt: time.Now().UTC()},
+	}
+)
+
+func getArticle(ctx context.Context, req GetArticleReq) (*Article, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	article, ok := articles[req.ID]
+	if !ok {
+		return nil, problem.NotFound("article not found")
+	}
+	copied := *article
+	return &copied, nil
+}
+
+// updateArticle 乐观并发控制, If-Match 与当前版本不一致时返回 412
+func updateArticle(ctx context.Context, req UpdateArticleReq) (*Article, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	article, ok := articles[req.ID]
+	if !ok {
+		return nil, problem.NotFound("article not found")
+	}
+	if err := web.ValidatePreconditions(ctx, article.ETag(), article.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	article.Title = req.Title
+	article.Version++
+	article.UpdatedAt = time.Now().UTC()
+
+	copied := *article
+	return &copied, nil
+}
+
+// deleteArticle gin 处理函数中使用 web.PreconditionFailed 校验 If-Match
+func deleteArticle(c *gin.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	article, ok := articles[id]
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if web.PreconditionFailed(c, article.ETag(), article.UpdatedAt) {
+		return
+	}
+
+	delete(articles, id)
+	c.Status(http.StatusNoContent)
+}
+
+// listArticles 未实现 ETag 的响应由路由配置按响应体生成 ETag
+func listArticles(c *gin.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	list := make([]Article, 0, len(articles))
+	for _, article := range articles {
+		list = append(list, *article)
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddProblemDetails()
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseResponseCompression()
+	app.UseHttpCaching()
+
+	api := app.MapGroup("/api/articles").
+		WithAllowAnonymous().
+		WithCacheControl(web.CacheControl{Private: true, NoCache: true})
+
+	api.MapGet("", listArticles).
+		WithETag(web.ETagWeak).
+		WithCacheControl(web.CacheControl{Public: true, MaxAge: 30 * time.Second})
+	web.MapHandle(api, web.GET, "/:id", getArticle)
+	web.MapHandle(api, web.PUT, "/:id", updateArticle)
+	api.MapDelete("/:id", deleteArticle)
+
+	app.MapGroup("/api").WithAllowAnonymous().
+		MapGet("/time", func(c *gin.Context) {
+			c.String(http.StatusOK, time.Now().Format(time.RFC3339Nano))
+		}).
+		WithCacheControl(web.CacheControl{NoStore: true})
+
+	app.Run()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/components/redisx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/db/sqlitex"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/dbctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/idempotency"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/redisctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type CreateOrderReq struct {
+	ProductID int64 `json:"productId" validate:"required,min=1"`
+	Quantity  int   `json:"quantity" validate:"required,min=1"`
+}
+
+type Order struct {
+	ID        int64     `json:"id"`
+	ProductID int64     `json:"productId"`
+	Quantity  int       `json:"quantity"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// StatusCode 创建成功返回 201
+func (Order) StatusCode() int {
+	return http.StatusCreated
+}
+
+// orderID 已创建的订单数, 重放的响应不会再次创建订单
+var orderID atomic.Int64
+
+// createOrder 模拟耗时的下单操作
+func createOrder(ctx context.Context, req CreateOrderReq) (Order, error) {
+	time.Sleep(500 * time.Millisecond)
+	return Order{
+		ID:        orderID.Add(1),
+		ProductID: req.ProductID,
+		Quantity:  req.Quantity,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	store := builder.Config().GetString("idempotency.store")
+	switch store {
+	case "redis":
+		builder.AddRedisContext(func(opts *redisctx.Options) {
+			opts.UseClient("default", func(cfg *redisx.Options) {
+				cfg.Addr = builder.Config().GetString("redis.addr")
+				cfg.Password = builder.Config().GetString("redis.password")
+				cfg.DB = builder.Config().GetInt("redis.db")
+			})
+		})
+	case "sqlite":
+		builder.AddDbContext(func(opts *dbctx.Options) {
+			opts.UseSQLite("default", func(cfg *sqlitex.Options) {
+				cfg.DSN = builder.Config().GetString("sqlite.dsn")
+			})
+		})
+	}
+
+	builder.AddProblemDetails()
+
+	builder.AddIdempotency(func(options *idempotency.Options) {
+		options.RequireKey = true
+		switch store {
+		case "redis":
+			options.UseStore(idempotency.NewRedisStore)
+		case "sqlite":
+			options.UseStore(idempotency.NewSqlStore)
+		}
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseIdempotency()
+
+	orders := app.MapGroup("/api/orders").WithAllowAnonymous()
+	web.MapHandle(orders, web.POST, "", createOrder).WithIdempotency()
+
+	app.Run()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/authz"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/openapi"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type User struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" description:"用户名"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type GetUserReq struct {
+	ID int64 `path:"id" validate:"required,min=1"`
+}
+
+type ListUsersReq struct {
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	PageSize int    `query:"pageSize" validate:"omitempty,min=1,max=100"`
+	Tenant   string `header:"X-Tenant"`
+}
+
+type CreateUserReq struct {
+	Name  string `json:"name" validate:"required,max=32" example:"alice"`
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=admin user"`
+}
+
+type Created[T any] struct {
+	Data T `json:"data"`
+}
+
+// StatusCode 创建成功返回 201
+func (Created[T]) StatusCode() int {
+	return http.StatusCreated
+}
+
+func getUser(ctx context.Context, req GetUserReq) (User, error) {
+	if req.ID != 1 {
+		return User{}, problem.NotFound("user not found")
+	}
+	return User{ID: 1, Name: "alice", CreatedAt: time.Now()}, nil
+}
+
+func listUsers(ctx context.Context, req ListUsersReq) ([]User, error) {
+	return []User{{ID: 1, Name: "alice", CreatedAt: time.Now()}}, nil
+}
+
+func createUser(ctx context.Context, req CreateUserReq) (Created[User], error) {
+	return Created[User]{Data: User{ID: 2, Name: req.Name, Email: req.Email, CreatedAt: time.Now()}}, nil
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddAuthentication(func(options *auth.Options) {
+		options.DefaultScheme = "local_jwt_bearer"
+		options.AddJwtBearer("local_jwt_bearer", func(options *jwt.Options) {
+			options.TokenValidationParameters = jwt.TokenValidationParameters{
+				ValidateIssuerSigningKey: true,
+				SigningKey:               []byte("secret"),
+			}
+		})
+	})
+
+	builder.AddAuthorization(func(options *authz.Options) {
+		options.RequireRole("admin", "admin")
+	})
+
+	builder.AddRateLimiter(func(opts *ratelimit.Options) {
+		opts.AddFixedWindowLimiter("fixed", func(opts *ratelimit.FixedWindowOptions) {
+			opts.PermitLimit = 100
+			opts.Window = time.Minute
+		})
+	})
+
+	builder.AddProblemDetails()
+
+	// 两个文档: public 与 admin, 未指定文档的路由出现在全部文档中
+	builder.AddOpenApi(func(options *openapi.Options) {
+		options.AddDocument("public", openapi.Info{Title: "Sample API", Version: "1.0.0"})
+		options.AddDocument("admin", openapi.Info{Title: "Sample Admin API", Version: "1.0.0"})
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseAuthentication()
+	app.UseAuthorization()
+	app.UseRateLimiter()
+	app.UseOpenApi()
+
+	users := app.MapGroup("/api/users").WithTags("users").WithRateLimiter("fixed")
+	web.MapHandle(users, web.GET, "", listUsers).
+		WithName("listUsers").
+		WithSummary("用户列表").
+		WithAllowAnonymous()
+	web.MapHandle(users, web.GET, "/:id", getUser).
+		WithName("getUser").
+		WithSummary("获取用户").
+		Produces(http.StatusNotFound, problem.Details{}, problem.ContentType)
+
+	admin := app.MapGroup("/api/admin").WithGroupName("admin").WithAuthorizationPolicy("admin")
+	web.MapHandle(admin, web.POST, "/users", createUser).
+		WithName("createUser").
+		WithSummary("创建用户")
+
+	app.Run()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/components/redisx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/outputcache"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/redisctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// calls 处理函数实际执行次数
+var calls atomic.Int64
+
+// products 慢查询, 缓存命中时不会执行
+func products(c *gin.Context) {
+	calls.Add(1)
+	time.Sleep(200 * time.Millisecond)
+	c.JSON(http.StatusOK, gin.H{
+		"category": c.Query("category"),
+		"items":    []string{"apple", "banana"},
+		"at":       time.Now().Format(time.RFC3339Nano),
+	})
+}
+
+// product 按路径参数区分缓存
+func product(c *gin.Context) {
+	calls.Add(1)
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "at": time.Now().Format(time.RFC3339Nano)})
+}
+
+// evict 按标签清除缓存, 缓存存储由容器注入
+func evict(store web.OutputCacheStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := store.EvictByTag(c.Request.Context(), "products"); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	useRedis := builder.Config().GetString("output_cache.store") == "redis"
+	if useRedis {
+		builder.AddRedisContext(func(opts *redisctx.Options) {
+			opts.UseClient("default", func(cfg *redisx.Options) {
+				cfg.Addr = builder.Config().GetString("redis.addr")
+				cfg.Password = builder.Config().GetString("redis.password")
+				cfg.DB = builder.Config().GetInt("redis.db")
+			})
+		})
+	}
+
+	builder.AddOutputCache(func(options *outputcache.Options) {
+		options.AddPolicy("products", func(policy *web.OutputCachePolicy) {
+			policy.Expire = 30 * time.Second
+			policy.VaryByQuery = []string{"category"}
+			policy.VaryByRouteValue = []string{"id"}
+			policy.Tags = []string{"products"}
+		})
+		if useRedis {
+			options.UseStore(outputcache.NewRedisStore)
+		}
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseResponseCompression()
+	app.UseOutputCache()
+
+	api := app.MapGroup("/api").WithAllowAnonymous()
+	api.MapGet("/products", products).WithOutputCache("products")
+	api.MapGet("/products/:id", product).WithOutputCache("products")
+	api.MapGet("/time", func(c *gin.Context) {
+		c.String(http.StatusOK, time.Now().Format(time.RFC3339Nano))
+	}).WithOutputCache()
+	api.MapPost("/products/evict", evict)
+	api.MapGet("/calls", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"calls": calls.Load()})
+	})
+
+	app.Run()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/localiza"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+)
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddLocalization(func(opts *localiza.Options) {
+		opts.DefaultLanguage = "zh-CN"
+		opts.SupportedLanguages = []string{"zh-CN", "en-US"}
+		opts.TranslationsDir = "./locales"
+		opts.FileType = localiza.LocalizationFileTypeJSON
+	})
+
+	builder.AddRateLimiter(func(opts *ratelimit.Options) {
+		opts.AddFixedWindowLimiter("strict", func(opts *ratelimit.FixedWindowOptions) {
+			opts.PermitLimit = 1
+			opts.Window = time.Minute
+		})
+	})
+
+	// 自定义错误映射: 数据库记录不存在映射为 404
+	builder.AddProblemDetails(func(opts *problem.Options) {
+		opts.TypeBaseURI = "https://example.com/problems/"
+		opts.AddMapper(func(err error) (*problem.Error, bool) {
+			if errors.Is(err, sql.ErrNoRows) {
+				return problem.NotFound("record not found").WithError(err), true
+			}
+			return nil, false
+		})
+	})
+
+	app := builder.Build()
+
+	// 国际化需先于异常处理中间件注册, 以便翻译标题
+	app.UseLocalization()
+	app.UseExceptionHandler()
+	app.UseRateLimiter()
+
+	app.MapRoute(func(router *gin.Engine) {
+		router.GET("/orders/:id", func(c *gin.Context) {
+			_ = c.Error(problem.NotFound("order " + c.Param("id") + " not found"))
+		})
+
+		router.GET("/users/:id", func(c *gin.Context) {
+			_ = c.Error(sql.ErrNoRows)
+		})
+
+		router.POST("/users", func(c *gin.Context) {
+			_ = c.Error(problem.Validation(
+				problem.FieldError{Field: "name", Message: "name is required", Code: "required"},
+				problem.FieldError{Field: "age", Message: "age must be at least 18", Code: "min"},
+			))
+		})
+
+		router.PUT("/users/:id", func(c *gin.Context) {
+			_ = c.Error(problem.Conflict("user version mismatch").WithExtension("currentVersion", 3))
+		})
+
+		router.GET("/panic", func(c *gin.Context) {
+			panic("something went wrong")
+		})
+
+		router.GET("/limited", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": "ok"})
+		}).WithRateLimit("strict")
+	})
+
+	app.Run()
+}
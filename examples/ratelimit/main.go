@@ -17,6 +17,7 @@ import (
 	_ "github.com/xiaohangshu-dev/go-workit/api/service1/docs" // swagger 一定要有这行,指向你的文档地址
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 )
 
 func main() {
@@ -30,6 +31,26 @@ func main() {
 			opts.Window = time.Minute                         // 时间窗口长度
 			opts.QueueProcessingOrder = ratelimit.OldestFirst // 可选，处理排队顺序
 		})
+		opts.AddTokenBucketLimiter("burst", func(opts *ratelimit.TokenBucketOptions) {
+			opts.TokenLimit = 10                   // 令牌桶容量
+			opts.TokensPerPeriod = 5               // 每周期补充的令牌数
+			opts.ReplenishmentPeriod = time.Second // 补充周期
+		})
+
+		// 端点组: 引用该组的路由共享组内限流器的计数
+		opts.AddEndpointGroup("search", "burst")
+
+		// 自适应并发限流: 根据请求耗时自动调整并发上限
+		opts.AddAdaptiveConcurrencyLimiter("adaptive", func(opts *ratelimit.AdaptiveConcurrencyOptions) {
+			opts.Algorithm = ratelimit.Gradient
+			opts.InitialLimit = 50
+			opts.MaxLimit = 500
+		})
+
+		// 负载削减: 以自适应限值作为容量, 饱和时先拒绝低优先级路由
+		opts.AddLoadShedder("shedder", func(opts *ratelimit.LoadShedderOptions) {
+			opts.AdaptivePolicy = "adaptive"
+		})
 	})
 
 	app := builder.Build()
@@ -37,11 +58,31 @@ func main() {
 	app.UseRateLimiter()
 
 	app.MapRoute(func(router *gin.Engine) {
+		// 全局限流器 default 始终生效
 		router.GET("/hello", func(c *gin.Context) {
 			c.JSON(200, gin.H{
 				"message": "Hello, World!",
 			})
 		})
+
+		// 先经过全局限流器 default, 再经过端点组 search
+		router.GET("/search/users", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": "users"})
+		}).WithRateLimit("search")
+
+		router.GET("/search/orders", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": "orders"})
+		}).WithRateLimit("search")
+
+		// 低优先级路由在服务饱和时最先被拒绝
+		router.GET("/reports", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": "reports"})
+		}).WithRateLimit("adaptive", ratelimit.PriorityPolicy("shedder", ratelimit.PriorityLow))
+
+		// 禁用限流
+		router.GET("/ping", func(c *gin.Context) {
+			c.String(200, "pong")
+		}).WithRateLimit(web.RateLimitDisabled)
 	})
 
 	app.Run()
@@ -11,16 +11,24 @@
 package main
 
 import (
+	"io"
+
 	"github.com/gin-gonic/gin"
 	_ "github.com/xiaohangshu-dev/go-workit/api/service1/docs" // swagger 一定要有这行,指向你的文档地址
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp"
 )
 
 func main() {
 
 	builder := webapp.NewBuilder()
 
-	builder.AddRequestDecompression()
+	builder.AddRequestDecompression(func(options *reqdecp.Options) {
+		options.MaxDecompressedSize = 10 << 20
+		options.MaxRatio = 100
+		// 大文件上传不受解压限制, 以流的形式读取
+		options.DisableLimits("/upload")
+	})
 
 	app := builder.Build()
 
@@ -30,6 +38,27 @@ func main() {
 				"message": "Hello, World!",
 			})
 		})
+
+		router.POST("/echo", func(c *gin.Context) {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(200, gin.H{
+				"length":         len(body),
+				"content_length": c.Request.ContentLength,
+			})
+		})
+
+		router.POST("/upload", func(c *gin.Context) {
+			n, err := io.Copy(io.Discard, c.Request.Body)
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(200, gin.H{"length": n})
+		})
 	})
 
 	app.UseRequestDecompression()
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/respcomp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddResponseCompression(func(opts *respcomp.Options) {
+		opts.MinSize = 512
+		opts.Level = web.CompressionFastest
+		opts.AddMimeTypes("application/x-ndjson")
+	})
+
+	app := builder.Build()
+
+	app.UseResponseCompression()
+
+	app.MapRoute(func(router *gin.Engine) {
+		// 超过最小压缩大小, 按 Accept-Encoding 压缩
+		router.GET("/large", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": strings.Repeat("hello ", 1000)})
+		})
+
+		// 未达到最小压缩大小, 不压缩
+		router.GET("/small", func(c *gin.Context) {
+			c.JSON(200, gin.H{"message": "hello"})
+		})
+
+		// 流式响应, 每次刷新都会输出已压缩的数据
+		router.GET("/events", func(c *gin.Context) {
+			c.Header("Content-Type", "text/event-stream")
+			for i := 0; i < 3; i++ {
+				fmt.Fprintf(c.Writer, "data: %d\n\n", i)
+				c.Writer.Flush()
+				time.Sleep(100 * time.Millisecond)
+			}
+		})
+	})
+
+	app.Run()
+}
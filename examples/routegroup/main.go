@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/authz"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+var secret = []byte("secret")
+
+type LoginReq struct {
+	User string `json:"user" validate:"required"`
+	Role string `json:"role" validate:"required"`
+}
+
+type LoginResp struct {
+	Token string `json:"token"`
+}
+
+func login(ctx context.Context, req LoginReq) (LoginResp, error) {
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{
+		"iss":  "sample",
+		"aud":  "sample",
+		"sub":  req.User,
+		"role": req.Role,
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	return LoginResp{Token: token}, err
+}
+
+// hello 处理函数构造器, 参数由容器注入
+func hello(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("hello")
+		c.JSON(200, gin.H{"message": "hello"})
+	}
+}
+
+// routes 输出路由表
+func routes(table web.RouteTable) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := make([]gin.H, 0)
+		for _, route := range table.Routes() {
+			result = append(result, gin.H{
+				"method":         route.Method,
+				"path":           route.Path,
+				"schemes":        route.Schemes,
+				"policies":       route.Policies,
+				"rateLimiter":    route.RateLimiter,
+				"allowAnonymous": route.AllowAnonymous,
+				"timeout":        route.Timeout.String(),
+			})
+		}
+		c.JSON(200, result)
+	}
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddAuthentication(func(options *auth.Options) {
+		options.DefaultScheme = "local_jwt_bearer"
+		options.AddJwtBearer("local_jwt_bearer", func(options *jwt.Options) {
+			options.TokenValidationParameters = jwt.TokenValidationParameters{
+				ValidateIssuer:           true,
+				ValidateAudience:         true,
+				ValidateLifetime:         true,
+				ValidateIssuerSigningKey: true,
+				SigningKey:               secret,
+				ValidIssuer:              "sample",
+				ValidAudience:            "sample",
+				RequireExpiration:        true,
+			}
+		})
+	})
+
+	builder.AddAuthorization(func(options *authz.Options) {
+		options.RequireRole("admin", "admin")
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseAuthentication()
+	app.UseAuthorization()
+	app.UseRouteDiagnostics()
+
+	// 匿名分组
+	public := app.MapGroup("/public").WithAllowAnonymous()
+	public.MapPost("/login", web.Handle(login))
+	public.MapGet("/routes", routes)
+
+	// 需要认证的分组, 子分组继承认证方案并追加授权策略
+	api := app.MapGroup("/api").
+		WithAuthenticationScheme("local_jwt_bearer").
+		WithTimeout(10 * time.Second)
+	api.MapGet("/hello", hello)
+
+	admin := api.MapGroup("/admin").WithAuthorizationPolicy("admin")
+	admin.MapGet("/users", func(c *gin.Context) {
+		c.JSON(200, []string{"alice", "bob"})
+	})
+	admin.MapDelete("/users/:id", func(c *gin.Context) {
+		c.Status(204)
+	}).WithTimeout(time.Second)
+
+	app.Run()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/antiforgery"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// page 表单以隐藏字段提交请求令牌, 内联脚本以 CSP nonce 放行并以请求头提交 cookie 中的令牌
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<body>
+  <form method="post" action="/api/comments">
+    <input type="hidden" name="{{.Tokens.FormFieldName}}" value="{{.Tokens.RequestToken}}">
+    <input name="text">
+    <button type="submit">submit</button>
+  </form>
+  <script nonce="{{.Nonce}}">
+    function token() {
+      var match = document.cookie.match(/(?:^|; )XSRF-TOKEN=([^;]*)/);
+      return match ? decodeURIComponent(match[1]) : "";
+    }
+    fetch("/api/comments", {method: "POST", headers: {"X-XSRF-TOKEN": token()}, body: new URLSearchParams({text: "from script"})})
+      .then(function (resp) { return resp.json(); })
+      .then(function (data) { console.log(data); });
+  </script>
+</body>
+</html>
+`))
+
+// index 渲染页面, 令牌与 nonce 均为当前请求生成
+func index(af web.Antiforgery) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		_ = page.Execute(c.Writer, gin.H{
+			"Tokens": af.GetAndStoreTokens(c),
+			"Nonce":  c.GetString(web.ContextCspNonceKey),
+		})
+	}
+}
+
+func comment(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"text": c.PostForm("text")})
+}
+
+// webhook 由第三方服务调用, 不经由浏览器, 不校验防伪令牌
+func webhook(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddProblemDetails()
+
+	// 多实例部署时须配置相同的签名密钥
+	builder.AddAntiforgery(func(options *antiforgery.Options) {
+		options.Pattern = antiforgery.DoubleSubmitCookie
+	})
+
+	app := builder.Build()
+
+	app.UseSecurityHeaders(func(options *web.SecurityHeadersOptions) {
+		options.ContentSecurityPolicy = "default-src 'self'; script-src 'self' 'nonce-{nonce}'; object-src 'none'; frame-ancestors 'none'"
+		options.PermissionsPolicy = "camera=(), microphone=(), geolocation=()"
+	})
+	app.UseExceptionHandler()
+	app.UseAntiforgery()
+
+	app.MapGroup("/").WithAllowAnonymous().MapGet("", index)
+
+	api := app.MapGroup("/api").WithAllowAnonymous()
+	api.MapPost("/comments", comment)
+	api.MapPost("/webhooks/payment", webhook).DisableAntiforgery()
+
+	app.Run()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/openapi"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/versioning"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type UserV1 struct {
+	Name string `json:"name"`
+}
+
+type UserV2 struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+type GetUserReq struct {
+	ID string `path:"id"`
+}
+
+// getUser 同一处理函数按请求的版本返回不同结构
+func getUser(ctx context.Context, req GetUserReq) (any, error) {
+	if version, _ := web.ApiVersionFromContext(ctx); version == "1" {
+		return UserV1{Name: "alice smith"}, nil
+	}
+	return UserV2{FirstName: "alice", LastName: "smith"}, nil
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	// 依次从 URL 路径段、查询参数、请求头与媒体类型读取版本
+	builder.AddApiVersioning(func(options *versioning.Options) {
+		options.DefaultVersion = "2"
+		options.AssumeDefaultVersionWhenUnspecified = true
+		options.ReadFromUrlSegment().
+			ReadFromQuery().
+			ReadFromHeader().
+			ReadFromMediaType()
+		options.AddDeprecationPolicy("1", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "https://example.com/api/v1-deprecation")
+		options.AddSunsetPolicy("1", time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), "https://example.com/api/v1-sunset")
+	})
+
+	// 每个版本一个文档
+	builder.AddOpenApi(func(options *openapi.Options) {
+		options.AddVersionDocument("1", openapi.Info{Title: "Sample API"})
+		options.AddVersionDocument("2", openapi.Info{Title: "Sample API"})
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseApiVersioning()
+	app.UseOpenApi()
+
+	// 查询参数、请求头或媒体类型指定版本: /api/users/1?api-version=1
+	users := app.MapGroup("/api/users").
+		WithAllowAnonymous().
+		WithApiVersions("2").
+		WithDeprecatedApiVersions("1")
+	web.MapHandle(users, web.GET, "/:id", getUser)
+
+	// URL 路径段指定版本
+	v1 := app.MapGroup("/api/v1/orders").WithAllowAnonymous().WithDeprecatedApiVersions("1")
+	v1.MapGet("", func(c *gin.Context) {
+		c.JSON(200, []string{"order-1"})
+	})
+
+	v2 := app.MapGroup("/api/v2/orders").WithAllowAnonymous().WithApiVersions("2")
+	v2.MapGet("", func(c *gin.Context) {
+		c.JSON(200, gin.H{"items": []string{"order-1"}, "total": 1})
+	})
+
+	// 与版本无关的路由
+	app.MapGroup("/").WithAllowAnonymous().MapGet("/ping", func(c *gin.Context) {
+		c.String(200, "pong")
+	})
+
+	app.Run()
+}
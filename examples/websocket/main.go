@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/xiaohangshu-dev/go-workit/pkg/components/redisx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/redisctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/websocket"
+	"go.uber.org/zap"
+)
+
+var secret = []byte("secret")
+
+type LoginReq struct {
+	User string `json:"user" validate:"required"`
+}
+
+type LoginResp struct {
+	Token string `json:"token"`
+}
+
+func login(ctx context.Context, req LoginReq) (LoginResp, error) {
+	token, err := jwtv5.NewWithClaims(jwtv5.SigningMethodHS256, jwtv5.MapClaims{
+		"iss": "sample",
+		"aud": "sample",
+		"sub": req.User,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(secret)
+	return LoginResp{Token: token}, err
+}
+
+// ChatMessage 聊天消息
+type ChatMessage struct {
+	Room string `json:"room"`
+	User string `json:"user"`
+	Text string `json:"text"`
+}
+
+// chat 聊天室, 连接加入路由参数指定的房间, 收到的消息广播到房间内的全部连接
+func chat(hub web.WebSocketHub, logger *zap.Logger) web.WebSocketHandler {
+	return func(conn web.WebSocketConn) error {
+		room := conn.Param("room")
+		user := conn.Principal().Subject
+
+		hub.Join(room, conn)
+		logger.Info("user joined", zap.String("room", room), zap.String("user", user))
+
+		for {
+			var msg ChatMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return err
+			}
+			msg.Room, msg.User = room, user
+			if err := hub.Broadcast(conn.Context(), room, msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// echo 匿名回显
+func echo(conn web.WebSocketConn) error {
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			return err
+		}
+	}
+}
+
+// notify 由 HTTP 接口向房间广播系统消息
+func notify(hub web.WebSocketHub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		msg := ChatMessage{Room: c.Param("room"), User: "system", Text: c.Query("text")}
+		if err := hub.Broadcast(c.Request.Context(), msg.Room, msg); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		c.Status(http.StatusAccepted)
+	}
+}
+
+func main() {
+
+	builder := webapp.NewBuilder()
+
+	builder.AddProblemDetails()
+
+	builder.AddAuthentication(func(options *auth.Options) {
+		options.DefaultScheme = "local_jwt_bearer"
+		options.AddJwtBearer("local_jwt_bearer", func(options *jwt.Options) {
+			options.TokenValidationParameters = jwt.TokenValidationParameters{
+				ValidateIssuer:           true,
+				ValidateAudience:         true,
+				ValidateLifetime:         true,
+				ValidateIssuerSigningKey: true,
+				SigningKey:               secret,
+				ValidIssuer:              "sample",
+				ValidAudience:            "sample",
+				RequireExpiration:        true,
+			}
+			// 浏览器无法为 WebSocket 握手设置 Authorization 请求头, 通过查询参数传递令牌
+			options.Events = &jwt.JwtBearerEvents{
+				OnMessageReceived: func(r *http.Request) (string, error) {
+					if strings.HasPrefix(r.URL.Path, "/ws/") {
+						return r.URL.Query().Get("access_token"), nil
+					}
+					return "", nil
+				},
+			}
+		})
+	})
+
+	useRedis := builder.Config().GetString("websocket.hub") == "redis"
+	if useRedis {
+		builder.AddRedisContext(func(opts *redisctx.Options) {
+			opts.UseClient("default", func(cfg *redisx.Options) {
+				cfg.Addr = builder.Config().GetString("redis.addr")
+				cfg.Password = builder.Config().GetString("redis.password")
+				cfg.DB = builder.Config().GetInt("redis.db")
+			})
+		})
+	}
+
+	builder.AddWebSockets(func(options *websocket.Options) {
+		options.PingInterval = 20 * time.Second
+		options.PongTimeout = 45 * time.Second
+		options.SendBufferSize = 64
+		// 示例允许任意来源, 生产环境应校验来源
+		options.CheckOrigin = func(r *http.Request) bool { return true }
+		if useRedis {
+			options.UseHub(websocket.NewRedisHub)
+		}
+	})
+
+	app := builder.Build()
+
+	app.UseExceptionHandler()
+	app.UseAuthentication()
+	app.UseAuthorization()
+
+	app.MapGroup("/auth").WithAllowAnonymous().MapPost("/login", web.Handle(login))
+
+	app.MapWebSocket("/ws/rooms/:room", chat).WithAuthenticationScheme("local_jwt_bearer")
+	app.MapWebSocket("/ws/echo", echo).WithAllowAnonymous()
+
+	app.MapGroup("/api/rooms").
+		WithAuthenticationScheme("local_jwt_bearer").
+		MapPost("/:room/notify", notify)
+
+	app.Run()
+}
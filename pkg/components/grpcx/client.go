@@ -0,0 +1,191 @@
+package grpcx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// NewClientConn 按选项创建 gRPC 客户端连接, 应用停止时关闭连接
+// 连接是惰性建立的, 首次调用时才会解析地址并连接
+func NewClientConn(lc fx.Lifecycle, name string, opts *Options, logger *zap.Logger) *grpc.ClientConn {
+	dialOptions, target, err := buildDialOptions(name, opts)
+	if err != nil {
+		logger.Error("Failed to build gRPC client options", zap.String("client", name), zap.Error(err))
+		panic(err)
+	}
+
+	conn, err := grpc.NewClient(target, dialOptions...)
+	if err != nil {
+		logger.Error("Failed to create gRPC client", zap.String("client", name), zap.Error(err))
+		panic(err)
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			logger.Info("gRPC client created", zap.String("client", name), zap.String("target", target))
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Closing gRPC client", zap.String("client", name))
+			return conn.Close()
+		},
+	})
+
+	return conn
+}
+
+// staticResolverScheme 静态地址列表解析器的 scheme
+const staticResolverScheme = "workit"
+
+func buildDialOptions(name string, opts *Options) ([]grpc.DialOption, string, error) {
+	creds, err := transportCredentials(opts.TLS)
+	if err != nil {
+		return nil, "", err
+	}
+
+	serviceConfig, err := buildServiceConfig(opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	target := opts.Target
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+	}
+
+	// 静态地址列表通过手动解析器注册, 由负载均衡策略在地址间分发
+	// 解析器仅对当前连接生效, 使用固定 scheme, 客户端名称放在 endpoint 中避免产生非法的 scheme
+	if len(opts.Endpoints) > 0 {
+		r := manual.NewBuilderWithScheme(staticResolverScheme)
+		addresses := make([]resolver.Address, 0, len(opts.Endpoints))
+		for _, endpoint := range opts.Endpoints {
+			addresses = append(addresses, resolver.Address{Addr: endpoint})
+		}
+		r.InitialState(resolver.State{Addresses: addresses})
+
+		target = r.Scheme() + ":///" + url.PathEscape(name)
+		dialOptions = append(dialOptions, grpc.WithResolvers(r))
+	}
+
+	if target == "" {
+		return nil, "", fmt.Errorf("grpc client %s: target or endpoints is required", name)
+	}
+
+	unary := []grpc.UnaryClientInterceptor{forwardUnary(opts), timeoutUnary(opts.Timeout)}
+	stream := []grpc.StreamClientInterceptor{forwardStream(opts)}
+
+	dialOptions = append(dialOptions,
+		grpc.WithChainUnaryInterceptor(append(unary, opts.UnaryInterceptors...)...),
+		grpc.WithChainStreamInterceptor(append(stream, opts.StreamInterceptors...)...),
+	)
+
+	return append(dialOptions, opts.DialOptions...), target, nil
+}
+
+// transportCredentials 构建传输层凭证
+func transportCredentials(opts TLSOptions) (credentials.TransportCredentials, error) {
+	if !opts.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         opts.ServerName,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid ca file: %s", opts.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// serviceConfig gRPC service config, 参见 https://github.com/grpc/grpc/blob/master/doc/service_config.md
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+	MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+}
+
+type methodConfig struct {
+	Name        []struct{}   `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+// buildServiceConfig 根据负载均衡与重试选项生成 service config
+func buildServiceConfig(opts *Options) (string, error) {
+	cfg := serviceConfig{}
+
+	if opts.LoadBalancing != "" {
+		cfg.LoadBalancingConfig = []map[string]struct{}{{opts.LoadBalancing: {}}}
+	}
+
+	if retry := opts.Retry; retry.MaxAttempts > 1 {
+		if retry.InitialBackoff <= 0 || retry.MaxBackoff <= 0 || retry.BackoffMultiplier <= 0 || len(retry.RetryableStatusCodes) == 0 {
+			return "", fmt.Errorf("invalid retry policy: backoff and retryable status codes are required")
+		}
+
+		// name 为空对象表示作用于所有方法
+		cfg.MethodConfig = []methodConfig{{
+			Name: []struct{}{{}},
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          retry.MaxAttempts,
+				InitialBackoff:       formatDuration(retry.InitialBackoff),
+				MaxBackoff:           formatDuration(retry.MaxBackoff),
+				BackoffMultiplier:    retry.BackoffMultiplier,
+				RetryableStatusCodes: retry.RetryableStatusCodes,
+			},
+		}}
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// formatDuration 转换为 service config 的时长格式, 如 0.1s
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -0,0 +1,96 @@
+package grpcx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// 透传的链路追踪头
+var traceKeys = []string{"traceparent", "tracestate", "x-request-id"}
+
+// authKey 透传的鉴权头
+const authKey = "authorization"
+
+type forwardedKey struct{}
+
+// WithForwardedHeaders 记录 HTTP 请求头, 在该上下文中发起的 gRPC 调用会按客户端配置透传鉴权与追踪头
+// gRPC 服务端处理函数中无需调用, 拦截器会直接读取传入的元数据
+func WithForwardedHeaders(ctx context.Context, header http.Header) context.Context {
+	md := metadata.MD{}
+	for _, key := range append([]string{authKey}, traceKeys...) {
+		if values := header.Values(key); len(values) > 0 {
+			md.Set(key, values...)
+		}
+	}
+	return context.WithValue(ctx, forwardedKey{}, md)
+}
+
+// forward 将调用方的鉴权与追踪头写入传出元数据, 已显式设置的键不会被覆盖
+func forward(ctx context.Context, opts *Options) context.Context {
+	keys := make([]string, 0, len(traceKeys)+1)
+	if opts.ForwardAuth {
+		keys = append(keys, authKey)
+	}
+	if opts.ForwardTrace {
+		keys = append(keys, traceKeys...)
+	}
+	if len(keys) == 0 {
+		return ctx
+	}
+
+	incoming, _ := metadata.FromIncomingContext(ctx)
+	forwarded, _ := ctx.Value(forwardedKey{}).(metadata.MD)
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		if len(outgoing.Get(key)) > 0 {
+			continue
+		}
+
+		values := incoming.Get(key)
+		if len(values) == 0 {
+			values = forwarded.Get(key)
+		}
+		for _, value := range values {
+			pairs = append(pairs, strings.ToLower(key), value)
+		}
+	}
+
+	if len(pairs) == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// forwardUnary 透传元数据的一元调用拦截器
+func forwardUnary(opts *Options) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		return invoker(forward(ctx, opts), method, req, reply, cc, callOpts...)
+	}
+}
+
+// forwardStream 透传元数据的流式调用拦截器
+func forwardStream(opts *Options) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(forward(ctx, opts), desc, cc, method, callOpts...)
+	}
+}
+
+// timeoutUnary 调用方未设置截止时间时使用默认超时
+func timeoutUnary(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, callOpts...)
+	}
+}
@@ -0,0 +1,74 @@
+package grpcx
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	RoundRobin = "round_robin" // 轮询负载均衡
+	PickFirst  = "pick_first"  // 首个可用地址
+)
+
+// Options gRPC 客户端选项, 可由配置 grpc_clients.<name> 加载
+type Options struct {
+	Target        string        `mapstructure:"target"`         // 目标地址, 支持 dns:///host:port 等解析方案
+	Endpoints     []string      `mapstructure:"endpoints"`      // 静态地址列表, 设置后忽略 Target 并在地址间负载均衡
+	Timeout       time.Duration `mapstructure:"timeout"`        // 单次调用超时, 调用方未设置截止时间时生效
+	LoadBalancing string        `mapstructure:"load_balancing"` // 负载均衡策略 round_robin / pick_first
+	TLS           TLSOptions    `mapstructure:"tls"`            // TLS 配置
+	Retry         RetryOptions  `mapstructure:"retry"`          // 重试策略
+	ForwardAuth   bool          `mapstructure:"forward_auth"`   // 是否透传调用方的 authorization, 默认关闭, 仅对可信的下游服务开启
+	ForwardTrace  bool          `mapstructure:"forward_trace"`  // 是否透传链路追踪头
+
+	DialOptions        []grpc.DialOption              `mapstructure:"-"` // 自定义连接选项
+	UnaryInterceptors  []grpc.UnaryClientInterceptor  `mapstructure:"-"` // 自定义一元调用拦截器
+	StreamInterceptors []grpc.StreamClientInterceptor `mapstructure:"-"` // 自定义流式调用拦截器
+}
+
+// TLSOptions TLS 配置, 未启用时使用明文连接
+type TLSOptions struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CAFile             string `mapstructure:"ca_file"`              // 服务端 CA 证书
+	CertFile           string `mapstructure:"cert_file"`            // 客户端证书(双向 TLS)
+	KeyFile            string `mapstructure:"key_file"`             // 客户端私钥(双向 TLS)
+	ServerName         string `mapstructure:"server_name"`          // 校验证书使用的服务名
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // 跳过证书校验, 仅用于测试
+}
+
+// RetryOptions 重试策略, 通过 service config 交由 gRPC 执行
+type RetryOptions struct {
+	MaxAttempts          int           `mapstructure:"max_attempts"`           // 最大尝试次数(含首次), 小于 2 表示不重试
+	InitialBackoff       time.Duration `mapstructure:"initial_backoff"`        // 初始退避时间
+	MaxBackoff           time.Duration `mapstructure:"max_backoff"`            // 最大退避时间
+	BackoffMultiplier    float64       `mapstructure:"backoff_multiplier"`     // 退避倍数
+	RetryableStatusCodes []string      `mapstructure:"retryable_status_codes"` // 可重试的状态码, 如 UNAVAILABLE
+}
+
+// NewOptions 创建 gRPC 客户端默认选项
+func NewOptions() *Options {
+	return &Options{
+		Timeout:       5 * time.Second,
+		LoadBalancing: RoundRobin,
+		ForwardTrace:  true,
+		Retry: RetryOptions{
+			MaxAttempts:          3,
+			InitialBackoff:       100 * time.Millisecond,
+			MaxBackoff:           time.Second,
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		},
+	}
+}
+
+// UseInterceptor 添加客户端拦截器
+func (o *Options) UseInterceptor(unary grpc.UnaryClientInterceptor, stream grpc.StreamClientInterceptor) *Options {
+	if unary != nil {
+		o.UnaryInterceptors = append(o.UnaryInterceptors, unary)
+	}
+	if stream != nil {
+		o.StreamInterceptors = append(o.StreamInterceptors, stream)
+	}
+	return o
+}
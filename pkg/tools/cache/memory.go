@@ -42,6 +42,33 @@ func (c *MemoryCache) Get(key string) (interface{}, bool) {
 	return nil, false
 }
 
+func (c *MemoryCache) Add(key string, value interface{}, expiration time.Duration) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if val, ok := c.data[key]; ok {
+		if exp, exists := c.exp[key]; !exists || exp.After(time.Now()) {
+			return val, false
+		}
+	}
+
+	c.data[key] = value
+	if expiration > 0 {
+		c.exp[key] = time.Now().Add(expiration)
+	} else {
+		delete(c.exp, key)
+	}
+	return nil, true
+}
+
+func (c *MemoryCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.data, key)
+	delete(c.exp, key)
+}
+
 func (c *MemoryCache) cleanExpired() {
 	ticker := time.NewTicker(time.Minute)
 	for range ticker.C {
@@ -0,0 +1,157 @@
+package antiforgery
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// contextCookieTokenKey 当前请求的 cookie 令牌在 gin 上下文中的键, 新生成的令牌在本次请求中即可使用
+const contextCookieTokenKey = "antiforgeryCookieToken"
+
+// Antiforgery 防伪令牌服务
+// cookie 令牌为随机值及其签名, 防止子域名写入伪造的 cookie; 同步令牌模式下请求令牌为 cookie 令牌与当前用户的签名
+type Antiforgery struct {
+	opts *Options
+	key  []byte
+}
+
+// NewAntiforgery 创建防伪令牌服务
+func NewAntiforgery(opts *Options) *Antiforgery {
+	key := opts.Key
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &Antiforgery{opts: opts, key: key}
+}
+
+// GetAndStoreTokens 返回请求令牌, cookie 令牌不存在或无效时生成并写出 cookie
+func (a *Antiforgery) GetAndStoreTokens(c *gin.Context) web.AntiforgeryTokens {
+	cookieToken, ok := a.cookieToken(c)
+	if !ok {
+		cookieToken = a.newCookieToken()
+		a.storeCookie(c, cookieToken)
+	}
+
+	requestToken := cookieToken
+	if a.opts.Pattern == SynchronizerToken {
+		requestToken = a.sign("request|" + cookieToken + "|" + subject(c))
+		// 包含请求令牌的页面不应被缓存
+		c.Header("Cache-Control", "no-cache, no-store")
+	}
+
+	return web.AntiforgeryTokens{
+		RequestToken:  requestToken,
+		HeaderName:    a.opts.HeaderName,
+		FormFieldName: a.opts.FormFieldName,
+	}
+}
+
+// Validate 校验 cookie 令牌签名与请求令牌
+func (a *Antiforgery) Validate(c *gin.Context) error {
+	cookieToken, ok := a.cookieToken(c)
+	if !ok {
+		return problem.Forbidden("the antiforgery cookie token is missing or invalid")
+	}
+
+	requestToken := c.GetHeader(a.opts.HeaderName)
+	if requestToken == "" && a.opts.FormFieldName != "" && isForm(c.ContentType()) {
+		requestToken = c.PostForm(a.opts.FormFieldName)
+	}
+	if requestToken == "" {
+		return problem.Forbidden("the antiforgery request token is missing")
+	}
+
+	expected := cookieToken
+	if a.opts.Pattern == SynchronizerToken {
+		expected = a.sign("request|" + cookieToken + "|" + subject(c))
+	}
+	if !hmac.Equal([]byte(requestToken), []byte(expected)) {
+		return problem.Forbidden("the antiforgery request token is invalid")
+	}
+	return nil
+}
+
+// EnsureCookie 双重提交模式下 cookie 令牌不存在或无效时生成并写出, 同步令牌模式下由 GetAndStoreTokens 写出
+func (a *Antiforgery) EnsureCookie(c *gin.Context) {
+	if a.opts.Pattern != DoubleSubmitCookie {
+		return
+	}
+	if _, ok := a.cookieToken(c); !ok {
+		a.storeCookie(c, a.newCookieToken())
+	}
+}
+
+// cookieToken 读取签名有效的 cookie 令牌
+func (a *Antiforgery) cookieToken(c *gin.Context) (string, bool) {
+	if token := c.GetString(contextCookieTokenKey); token != "" {
+		return token, true
+	}
+
+	token, err := c.Cookie(a.opts.CookieName)
+	if err != nil || !a.verifyCookieToken(token) {
+		return "", false
+	}
+	c.Set(contextCookieTokenKey, token)
+	return token, true
+}
+
+// newCookieToken 生成 cookie 令牌: 随机值.签名
+func (a *Antiforgery) newCookieToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	value := base64.RawURLEncoding.EncodeToString(b)
+	return value + "." + a.sign("cookie|"+value)
+}
+
+func (a *Antiforgery) verifyCookieToken(token string) bool {
+	value, signature, ok := strings.Cut(token, ".")
+	if !ok || value == "" {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(a.sign("cookie|"+value)))
+}
+
+// storeCookie 写出 cookie 令牌, 双重提交模式下前端脚本须能读取 cookie, 因此不设置 HttpOnly
+func (a *Antiforgery) storeCookie(c *gin.Context, token string) {
+	cookie := &http.Cookie{
+		Name:     a.opts.CookieName,
+		Value:    token,
+		Path:     a.opts.CookiePath,
+		Domain:   a.opts.CookieDomain,
+		Secure:   a.opts.CookieSecure || c.Request.TLS != nil,
+		HttpOnly: a.opts.Pattern == SynchronizerToken,
+		SameSite: a.opts.SameSite,
+	}
+	if a.opts.CookieMaxAge > 0 {
+		cookie.MaxAge = int(a.opts.CookieMaxAge.Seconds())
+	}
+	http.SetCookie(c.Writer, cookie)
+	c.Set(contextCookieTokenKey, token)
+}
+
+func (a *Antiforgery) sign(value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// subject 当前用户标识, 同步令牌与用户绑定, 登录前后需重新获取令牌
+func subject(c *gin.Context) string {
+	if principal, ok := web.ClaimsPrincipalFromContext(c.Request.Context()); ok {
+		return principal.Subject
+	}
+	return ""
+}
+
+func isForm(contentType string) bool {
+	return contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data"
+}
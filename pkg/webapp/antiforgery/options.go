@@ -0,0 +1,42 @@
+package antiforgery
+
+import (
+	"net/http"
+	"time"
+)
+
+// Pattern 防伪令牌模式
+type Pattern string
+
+const (
+	// DoubleSubmitCookie 双重提交 cookie, cookie 可由前端脚本读取, 请求以请求头提交相同的值, 适用于单页应用
+	DoubleSubmitCookie Pattern = "double-submit"
+	// SynchronizerToken 同步令牌, cookie 仅服务端可读, 请求令牌由服务端渲染到页面或表单, 适用于服务端渲染页面
+	SynchronizerToken Pattern = "synchronizer"
+)
+
+// Options 防伪令牌配置
+type Options struct {
+	Pattern       Pattern
+	Key           []byte        // 签名密钥, 多实例部署时须配置相同的密钥, 为空时启动时随机生成
+	CookieName    string        // 令牌 cookie 名称
+	CookiePath    string        // 令牌 cookie 路径
+	CookieDomain  string        // 令牌 cookie 域名
+	CookieSecure  bool          // 令牌 cookie 是否仅经 HTTPS 发送, 未启用时 HTTPS 请求仍写出 Secure cookie
+	CookieMaxAge  time.Duration // 令牌 cookie 有效期, 为 0 时为会话 cookie
+	SameSite      http.SameSite // 令牌 cookie 的 SameSite 属性
+	HeaderName    string        // 请求令牌请求头
+	FormFieldName string        // 请求令牌表单字段, 请求头不存在时读取
+}
+
+// NewOptions 返回默认配置, 默认使用双重提交 cookie 模式
+func NewOptions() *Options {
+	return &Options{
+		Pattern:       DoubleSubmitCookie,
+		CookieName:    "XSRF-TOKEN",
+		CookiePath:    "/",
+		SameSite:      http.SameSiteLaxMode,
+		HeaderName:    "X-XSRF-TOKEN",
+		FormFieldName: "__RequestVerificationToken",
+	}
+}
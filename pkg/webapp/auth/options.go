@@ -5,16 +5,23 @@ import (
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 )
 
+// grpcHealthMethods 框架注册的 gRPC 健康检查服务, 默认允许匿名访问, 供 Kubernetes 与 grpc-health-probe 探测
+// 反射服务不在此列, 需要时通过 AllowAnonymousGrpcMethod 显式开放
+const grpcHealthMethods = "/grpc.health.v1.Health/*"
+
 // Options 表示授权选项配置。
 type Options struct {
 	DefaultScheme string
 	schemes       map[string]web.Authenticate
+	grpcMethods   []web.GrpcMethodRule // gRPC 方法鉴权方案
+	grpcAnonymous []web.GrpcMethodRule // gRPC 允许匿名访问的方法
 }
 
-// NewOptions 创建一个新的 Options 实例
+// NewOptions 创建一个新的 Options 实例, 健康检查服务默认允许匿名访问
 func NewOptions() *Options {
 	opt := &Options{
-		schemes: make(map[string]web.Authenticate),
+		schemes:       make(map[string]web.Authenticate),
+		grpcAnonymous: []web.GrpcMethodRule{web.NewGrpcMethodRule(grpcHealthMethods)},
 	}
 
 	return opt
@@ -47,3 +54,28 @@ func (o *Options) AddJwtBearer(schemeName string, fn func(options *jwt.Options))
 
 	return o
 }
+
+// MapGrpcMethod 为匹配的 gRPC 方法配置鉴权方案, 未配置的方法使用默认鉴权方案
+// pattern 为完整方法名, 支持通配符, 如 /hello.Greeter/*
+func (o *Options) MapGrpcMethod(pattern string, schemes ...string) *Options {
+	o.grpcMethods = append(o.grpcMethods, web.NewGrpcMethodRule(pattern, schemes...))
+	return o
+}
+
+// AllowAnonymousGrpcMethod 允许匹配的 gRPC 方法匿名访问
+func (o *Options) AllowAnonymousGrpcMethod(patterns ...string) *Options {
+	for _, pattern := range patterns {
+		o.grpcAnonymous = append(o.grpcAnonymous, web.NewGrpcMethodRule(pattern))
+	}
+	return o
+}
+
+// GrpcMethods 返回所有 gRPC 方法鉴权配置
+func (o *Options) GrpcMethods() []web.GrpcMethodRule {
+	return o.grpcMethods
+}
+
+// GrpcAnonymousMethods 返回所有允许匿名访问的 gRPC 方法
+func (o *Options) GrpcAnonymousMethods() []web.GrpcMethodRule {
+	return o.grpcAnonymous
+}
@@ -8,6 +8,7 @@ import (
 type Options struct {
 	DefaultPolicy string
 	policys       map[string]func(*web.ClaimsPrincipal) bool
+	grpcMethods   []web.GrpcMethodRule // gRPC 方法授权策略
 }
 
 // NewOptions 创建一个新的 Options 实例
@@ -83,3 +84,15 @@ func (o *Options) RequireHasChaims(policyName, k string) *Options {
 
 	return o
 }
+
+// MapGrpcMethod 为匹配的 gRPC 方法配置授权策略, 未配置的方法使用默认授权策略
+// pattern 为完整方法名, 支持通配符, 如 /hello.Greeter/*
+func (o *Options) MapGrpcMethod(pattern string, policies ...string) *Options {
+	o.grpcMethods = append(o.grpcMethods, web.NewGrpcMethodRule(pattern, policies...))
+	return o
+}
+
+// GrpcMethods 返回所有 gRPC 方法授权配置
+func (o *Options) GrpcMethods() []web.GrpcMethodRule {
+	return o.grpcMethods
+}
@@ -0,0 +1,62 @@
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// defaultMethods 策略未配置请求方法时允许的方法
+var defaultMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// Cors 跨域策略管理
+type Cors struct {
+	opts *Options
+}
+
+// NewCors 创建跨域策略管理, 默认策略不存在或策略配置无效时返回错误
+func NewCors(opts *Options) (*Cors, error) {
+	if opts.DefaultPolicy != "" {
+		if _, ok := opts.policies[opts.DefaultPolicy]; !ok {
+			return nil, fmt.Errorf("default cors policy %q not found", opts.DefaultPolicy)
+		}
+	}
+
+	for name, policy := range opts.policies {
+		if len(policy.AllowMethods) == 0 {
+			policy.AllowMethods = defaultMethods
+		}
+		for i, method := range policy.AllowMethods {
+			policy.AllowMethods[i] = strings.ToUpper(method)
+		}
+		for i, origin := range policy.AllowOrigins {
+			policy.AllowOrigins[i] = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		}
+		if policy.AllowCredentials && slices.Contains(policy.AllowOrigins, "*") {
+			return nil, fmt.Errorf("cors policy %q: allow_credentials cannot be used with allow_origins *", name)
+		}
+		if policy.ValidateOrigin && opts.validator == nil {
+			return nil, fmt.Errorf("cors policy %q: validate_origin requires an origin validator", name)
+		}
+	}
+
+	return &Cors{opts: opts}, nil
+}
+
+// Policy 返回跨域策略, 名称为空时返回默认策略
+func (c *Cors) Policy(name string) (*web.CorsPolicy, bool) {
+	if name == "" {
+		name = c.opts.DefaultPolicy
+		if name == "" {
+			return nil, false
+		}
+	}
+
+	policy, ok := c.opts.policies[name]
+	return policy, ok
+}
@@ -0,0 +1,67 @@
+package cors
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// Options 跨域配置
+type Options struct {
+	DefaultPolicy string // 默认策略名称, 路由未指定策略时使用, 为空时未指定策略的路由不处理跨域请求
+	policies      map[string]*web.CorsPolicy
+	validator     any
+}
+
+// NewOptions 返回默认配置
+func NewOptions() *Options {
+	return &Options{
+		policies: make(map[string]*web.CorsPolicy),
+	}
+}
+
+// LoadConfig 从配置 cors.default_policy 与 cors.policies.<name> 加载跨域策略
+func (o *Options) LoadConfig(config *viper.Viper) error {
+	if name := config.GetString("cors.default_policy"); name != "" {
+		o.DefaultPolicy = name
+	}
+
+	for name := range config.GetStringMap("cors.policies") {
+		policy := &web.CorsPolicy{}
+		if err := config.UnmarshalKey("cors.policies."+name, policy); err != nil {
+			return fmt.Errorf("load cors policy %s config: %w", name, err)
+		}
+		o.policies[name] = policy
+	}
+	return nil
+}
+
+// AddPolicy 添加跨域策略, 同名策略已从配置加载时在其基础上修改
+func (o *Options) AddPolicy(name string, fn func(policy *web.CorsPolicy)) *Options {
+	policy, ok := o.policies[name]
+	if !ok {
+		policy = &web.CorsPolicy{}
+		o.policies[name] = policy
+	}
+	fn(policy)
+	return o
+}
+
+// AddDefaultPolicy 添加跨域策略并设为默认策略
+func (o *Options) AddDefaultPolicy(name string, fn func(policy *web.CorsPolicy)) *Options {
+	o.DefaultPolicy = name
+	return o.AddPolicy(name, fn)
+}
+
+// UseOriginValidator 使用动态来源校验, constructor 为返回 web.CorsOriginValidator 实现的构造函数, 参数由容器注入
+// 仅用于开启 ValidateOrigin 的策略
+func (o *Options) UseOriginValidator(constructor any) *Options {
+	o.validator = constructor
+	return o
+}
+
+// OriginValidator 返回动态来源校验的构造函数, 未配置时返回 nil
+func (o *Options) OriginValidator() any {
+	return o.validator
+}
@@ -0,0 +1,81 @@
+package ginx
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+// safeMethods 不修改状态的请求方法, 不校验防伪令牌
+var safeMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+}
+
+// Antiforgery 防伪令牌中间件
+type Antiforgery struct {
+	antiforgery web.Antiforgery
+	routes      *routeLookup
+	logger      *zap.Logger
+}
+
+// newAntiforgery 初始化防伪令牌中间件
+func newAntiforgery(antiforgery web.Antiforgery, routes web.RouteTable, logger *zap.Logger) *Antiforgery {
+	return &Antiforgery{
+		antiforgery: antiforgery,
+		routes:      newRouteLookup(routes),
+		logger:      logger,
+	}
+}
+
+// Handle 防伪令牌中间件处理函数
+// 安全方法、携带 Bearer 令牌(不依赖 cookie 认证, 不受 CSRF 影响)与禁用防伪校验的路由不校验;
+// 双重提交模式下安全方法的请求在 cookie 不存在时写出 cookie, 供前端脚本读取
+func (m *Antiforgery) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := safeMethods[c.Request.Method]; ok {
+			m.antiforgery.EnsureCookie(c)
+			c.Next()
+			return
+		}
+
+		if isBearer(c.GetHeader("Authorization")) {
+			c.Next()
+			return
+		}
+		if route := m.routes.find(c); route != nil && route.AntiforgeryDisabled {
+			c.Next()
+			return
+		}
+
+		if err := m.antiforgery.Validate(c); err != nil {
+			m.logger.Info("antiforgery token validation failed",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("method", c.Request.Method),
+				zap.Error(err))
+
+			e, ok := problem.As(err)
+			if !ok {
+				e = problem.Forbidden(err.Error())
+			}
+			abortWithProblem(c, e, func() {
+				c.AbortWithStatusJSON(e.Status, gin.H{"error": e.Detail})
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// isBearer 请求是否以 Bearer 令牌认证
+func isBearer(authorization string) bool {
+	scheme, _, ok := strings.Cut(authorization, " ")
+	return ok && strings.EqualFold(scheme, "bearer")
+}
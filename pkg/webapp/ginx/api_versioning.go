@@ -0,0 +1,152 @@
+package ginx
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/versioning"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+// ApiVersioning API 版本中间件
+type ApiVersioning struct {
+	web.ApiVersioning
+	routes *routeLookup
+	logger *zap.Logger
+}
+
+// versionedRoute 路由声明的版本, 均已规范化
+type versionedRoute struct {
+	supported  []string
+	deprecated []string
+}
+
+// newApiVersioning 初始化 API 版本中间件
+func newApiVersioning(versioning web.ApiVersioning, routes web.RouteTable, logger *zap.Logger) *ApiVersioning {
+	return &ApiVersioning{
+		ApiVersioning: versioning,
+		routes:        newRouteLookup(routes),
+		logger:        logger,
+	}
+}
+
+// Handle API 版本中间件处理函数
+// 与版本无关的路由直接放行; 版本化路由请求不支持的版本时返回 400 并输出 api-supported-versions 头,
+// 请求已弃用的版本时输出 Deprecation 头, 配置了下线策略时输出 Sunset 头
+func (m *ApiVersioning) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, err := m.Read(c.Request)
+
+		route := m.route(c)
+		if route == nil {
+			if err == nil {
+				setApiVersion(c, version)
+			}
+			c.Next()
+			return
+		}
+
+		if m.ReportApiVersions() {
+			m.reportVersions(c, route)
+		}
+
+		if err == nil && !slices.Contains(route.supported, version) && !slices.Contains(route.deprecated, version) {
+			err = errUnsupportedVersion(version)
+		}
+		if err != nil {
+			m.logger.Info("api version rejected",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("method", c.Request.Method),
+				zap.Error(err))
+
+			m.reportVersions(c, route)
+			abortWithProblem(c, problem.BadRequest(err.Error()).WithError(err), func() {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+			})
+			return
+		}
+
+		if slices.Contains(route.deprecated, version) {
+			m.writeDeprecation(c, version)
+		}
+		if policy, ok := m.Sunset(version); ok {
+			c.Header("Sunset", policy.Date.UTC().Format(http.TimeFormat))
+			if policy.Link != "" {
+				c.Writer.Header().Add("Link", "<"+policy.Link+`>; rel="sunset"`)
+			}
+		}
+
+		setApiVersion(c, version)
+		c.Next()
+	}
+}
+
+// route 返回当前请求匹配的版本化路由, 与版本无关的路由返回 nil
+func (m *ApiVersioning) route(c *gin.Context) *versionedRoute {
+	route := m.routes.find(c)
+	if route == nil || !route.Versioned() {
+		return nil
+	}
+
+	versioned := &versionedRoute{}
+	for _, version := range route.ApiVersions {
+		versioned.supported = appendVersion(versioned.supported, version)
+	}
+	for _, version := range route.DeprecatedApiVersions {
+		versioned.deprecated = appendVersion(versioned.deprecated, version)
+	}
+	return versioned
+}
+
+// reportVersions 输出路由支持与弃用的版本
+func (m *ApiVersioning) reportVersions(c *gin.Context, route *versionedRoute) {
+	if len(route.supported) > 0 {
+		c.Header("api-supported-versions", strings.Join(route.supported, ", "))
+	}
+	if len(route.deprecated) > 0 {
+		c.Header("api-deprecated-versions", strings.Join(route.deprecated, ", "))
+	}
+}
+
+// writeDeprecation 输出弃用头, 配置了弃用时间时按 RFC 9745 输出时间戳
+func (m *ApiVersioning) writeDeprecation(c *gin.Context, version string) {
+	policy, ok := m.Deprecation(version)
+	if !ok || policy.Date.IsZero() {
+		c.Header("Deprecation", "true")
+	} else {
+		c.Header("Deprecation", "@"+strconv.FormatInt(policy.Date.Unix(), 10))
+	}
+
+	if ok && policy.Link != "" {
+		c.Writer.Header().Add("Link", "<"+policy.Link+`>; rel="deprecation"; type="text/html"`)
+	}
+}
+
+// setApiVersion 记录请求的版本, 处理函数可通过 web.ApiVersionFromContext 读取
+func setApiVersion(c *gin.Context, version string) {
+	c.Set(web.ContextApiVersionKey, version)
+	c.Request = c.Request.WithContext(web.WithApiVersion(c.Request.Context(), version))
+}
+
+// appendVersion 追加规范化后不重复的版本
+func appendVersion(versions []string, version string) []string {
+	version = versioning.Normalize(version)
+	if slices.Contains(versions, version) {
+		return versions
+	}
+	return append(versions, version)
+}
+
+// errUnsupportedVersion 请求的版本不受路由支持
+type errUnsupportedVersion string
+
+func (e errUnsupportedVersion) Error() string {
+	return "unsupported api version " + string(e)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 	"go.uber.org/zap"
 )
@@ -37,7 +38,7 @@ func (a *Authenticate) Handle() gin.HandlerFunc {
 			zap.String("ip", ip),
 		}
 
-		nodeValue := a.GetNodeValue(c)
+		nodeValue := routeMetadata(a.Engine, c)
 		// 跳过不需要授权的路由
 		if nodeValue.AllowAnonymous {
 			c.Next()
@@ -50,7 +51,9 @@ func (a *Authenticate) Handle() gin.HandlerFunc {
 				schemes = append(schemes, defaultScheme)
 			} else {
 				a.logger.Error("route not configured with scheme", commonFields...)
-				c.AbortWithStatus(http.StatusUnauthorized)
+				abortWithProblem(c, problem.Unauthorized(""), func() {
+					c.AbortWithStatus(http.StatusUnauthorized)
+				})
 				return
 			}
 		}
@@ -67,6 +70,7 @@ func (a *Authenticate) Handle() gin.HandlerFunc {
 			claims, err := handler.Authenticate(req)
 			if err == nil && claims != nil {
 				c.Set(contextClaimsKey, claims)
+				c.Request = c.Request.WithContext(web.WithClaimsPrincipal(c.Request.Context(), claims))
 				c.Next() // 认证成功，继续下一个中间件/handler
 				return
 			}
@@ -80,6 +84,8 @@ func (a *Authenticate) Handle() gin.HandlerFunc {
 		}
 
 		// 所有 scheme 都认证失败
-		c.AbortWithStatus(http.StatusUnauthorized)
+		abortWithProblem(c, problem.Unauthorized(""), func() {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		})
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 	"go.uber.org/zap"
 )
@@ -36,7 +37,7 @@ func (a *Authorize) Handle() gin.HandlerFunc {
 			return
 		}
 
-		nodeValue := a.GetNodeValue(c)
+		nodeValue := routeMetadata(a.Engine, c)
 		if nodeValue.AllowAnonymous {
 			c.Next()
 			return
@@ -45,26 +46,34 @@ func (a *Authorize) Handle() gin.HandlerFunc {
 		claims := ginGetClaimsPrincipal(c)
 		if claims == nil {
 			a.logger.Error("authorization failed: ClaimsPrincipal is nil")
-			c.AbortWithStatus(http.StatusUnauthorized)
+			abortWithProblem(c, problem.Unauthorized(""), func() {
+				c.AbortWithStatus(http.StatusUnauthorized)
+			})
 			return
 		}
 
 		policyNames := nodeValue.AuthzPolicies
 
 		for _, policyName := range policyNames {
+			// 未注册的策略视为授权失败, 避免误配置时跳过授权
 			policyFunc, ok := a.Authorize(policyName)
 			if !ok {
-				a.logger.Warn("authorization failed: policy not found",
+				a.logger.Error("authorization failed: policy not found",
 					zap.String("path", path),
 					zap.String("policy", policyName))
-				continue
+				abortWithProblem(c, problem.Forbidden(""), func() {
+					c.AbortWithStatus(http.StatusForbidden)
+				})
+				return
 			}
 
 			if !policyFunc(claims) {
 				a.logger.Warn("authorization failed",
 					zap.String("path", path),
 					zap.String("policy", policyName))
-				c.AbortWithStatus(http.StatusForbidden)
+				abortWithProblem(c, problem.Forbidden(""), func() {
+					c.AbortWithStatus(http.StatusForbidden)
+				})
 				return
 			}
 		}
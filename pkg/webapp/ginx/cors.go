@@ -0,0 +1,201 @@
+package ginx
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Cors 跨域中间件
+type Cors struct {
+	web.Cors
+	validator web.CorsOriginValidator
+	routes    *routeLookup
+	logger    *zap.Logger
+}
+
+// corsDeps 跨域中间件的依赖, 动态来源校验为可选
+type corsDeps struct {
+	fx.In
+	Cors      web.Cors
+	Validator web.CorsOriginValidator `optional:"true"`
+	Routes    web.RouteTable
+	Logger    *zap.Logger
+}
+
+// newCors 初始化跨域中间件
+func newCors(deps corsDeps) *Cors {
+	return &Cors{
+		Cors:      deps.Cors,
+		validator: deps.Validator,
+		routes:    newRouteLookup(deps.Routes),
+		logger:    deps.Logger,
+	}
+}
+
+// Handle 跨域中间件处理函数
+// 路由通过 WithCors 选择策略, 未选择时使用默认策略; 预检请求按 Access-Control-Request-Method 查找实际请求的路由,
+// 由本中间件响应 204 并中止, 不再经过鉴权; 未匹配策略的预检请求与普通请求一样经过后续中间件;
+// 来源不被允许时预检请求返回 403, 普通请求不输出跨域响应头, 由浏览器拦截
+func (m *Cors) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		preflight := isPreflight(c.Request)
+
+		var route *web.RouteConfig
+		if preflight {
+			route = m.routes.match(strings.ToUpper(c.GetHeader("Access-Control-Request-Method")), c.Request.URL.Path)
+		} else {
+			route = m.routes.find(c)
+		}
+
+		policyName := ""
+		if route != nil {
+			policyName = route.CorsPolicy
+		}
+		policy, ok := m.Policy(policyName)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+
+		if !m.allowOrigin(c, policy, policyName, origin) {
+			if preflight {
+				m.reject(c, "origin "+origin+" is not allowed")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if policy.AllowCredentials || !slices.Contains(policy.AllowOrigins, "*") {
+			header.Set("Access-Control-Allow-Origin", origin)
+		} else {
+			header.Set("Access-Control-Allow-Origin", "*")
+		}
+		if policy.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(policy.ExposeHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposeHeaders, ", "))
+			}
+			c.Next()
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+
+		method := strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))
+		if !slices.Contains(policy.AllowMethods, method) {
+			m.reject(c, "method "+method+" is not allowed")
+			return
+		}
+
+		requested := c.GetHeader("Access-Control-Request-Headers")
+		if requested != "" {
+			if !allowHeaders(policy.AllowHeaders, requested) {
+				m.reject(c, "headers "+requested+" are not allowed")
+				return
+			}
+			// 允许全部请求头时回显请求的请求头, 携带凭据时浏览器不接受 *
+			header.Set("Access-Control-Allow-Headers", requested)
+		}
+
+		header.Set("Access-Control-Allow-Methods", strings.Join(policy.AllowMethods, ", "))
+		if policy.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allowOrigin 按静态列表、自定义函数与动态来源校验依次判断来源是否被允许
+func (m *Cors) allowOrigin(c *gin.Context, policy *web.CorsPolicy, policyName, origin string) bool {
+	normalized := strings.ToLower(origin)
+	for _, allowed := range policy.AllowOrigins {
+		if allowed == "*" || allowed == normalized || matchWildcardOrigin(allowed, normalized) {
+			return true
+		}
+	}
+
+	if policy.AllowOriginFunc != nil && policy.AllowOriginFunc(origin) {
+		return true
+	}
+
+	if policy.ValidateOrigin && m.validator != nil {
+		ok, err := m.validator.ValidateOrigin(c.Request, policyName, origin)
+		if err != nil {
+			m.logger.Error("failed to validate cors origin", zap.String("origin", origin), zap.Error(err))
+			return false
+		}
+		return ok
+	}
+	return false
+}
+
+// reject 拒绝预检请求
+func (m *Cors) reject(c *gin.Context, detail string) {
+	m.logger.Info("cors preflight rejected",
+		zap.String("path", c.Request.URL.Path),
+		zap.String("origin", c.GetHeader("Origin")),
+		zap.String("reason", detail))
+
+	abortWithProblem(c, problem.Forbidden(detail), func() {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": detail})
+	})
+}
+
+// isPreflight 是否为跨域预检请求
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// matchWildcardOrigin 匹配 https://*.example.com 形式的来源, 通配符仅匹配子域名
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix := scheme + "://"
+	if !strings.HasPrefix(origin, prefix) {
+		return false
+	}
+	sub := strings.TrimPrefix(origin, prefix)
+	return strings.HasSuffix(sub, "."+host) && len(sub) > len(host)+1
+}
+
+// allowHeaders 请求的请求头是否均被允许
+func allowHeaders(allowed []string, requested string) bool {
+	if slices.Contains(allowed, "*") {
+		return true
+	}
+	for _, name := range strings.Split(requested, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(allowed, func(h string) bool { return strings.EqualFold(h, name) }) {
+			return false
+		}
+	}
+	return true
+}
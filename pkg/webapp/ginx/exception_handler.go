@@ -0,0 +1,177 @@
+package ginx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+// ExceptionHandler 异常处理中间件, 将错误与 panic 渲染为 RFC 7807 Problem Details
+type ExceptionHandler struct {
+	web.ProblemMapper
+	env    *web.Environment
+	logger *zap.Logger
+}
+
+// newExceptionHandler 初始化异常处理中间件
+func newExceptionHandler(mapper web.ProblemMapper, env *web.Environment, logger *zap.Logger) *ExceptionHandler {
+	return &ExceptionHandler{
+		ProblemMapper: mapper,
+		env:           env,
+		logger:        logger,
+	}
+}
+
+// Handle 异常处理中间件处理函数
+// handler 通过 c.Error 记录错误且未写入响应时, 以最后一个错误渲染响应
+func (h *ExceptionHandler) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(web.ContextExceptionHandlerKey, h)
+
+		defer func() {
+			if r := recover(); r != nil {
+				// 中止响应的 panic 交由 net/http 处理
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				err, ok := r.(error)
+				if !ok {
+					err = fmt.Errorf("%v", r)
+				}
+
+				h.logger.Error("panic recovered",
+					zap.Any("error", r),
+					zap.String("path", c.Request.URL.Path),
+					zap.String("method", c.Request.Method),
+					zap.String("ip", c.ClientIP()),
+					zap.ByteString("stack", debug.Stack()),
+				)
+
+				c.Abort()
+				if !c.Writer.Written() {
+					h.render(c, problem.Internal(err))
+				}
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		h.render(c, c.Errors.Last().Err)
+	}
+}
+
+// render 将错误渲染为 Problem Details 响应
+func (h *ExceptionHandler) render(c *gin.Context, err error) {
+	e := h.Map(err)
+
+	details := problem.Details{
+		Type:       e.Type,
+		Title:      localizeTitle(c, e),
+		Status:     e.Status,
+		Detail:     e.Detail,
+		Instance:   c.Request.URL.Path,
+		TraceID:    traceID(c),
+		Errors:     e.Errors,
+		Extensions: e.Extensions,
+	}
+	if details.Type == "" {
+		details.Type = "about:blank"
+	}
+
+	if e.Status >= http.StatusInternalServerError {
+		h.logger.Error("request failed",
+			zap.Int("status", e.Status),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("method", c.Request.Method),
+			zap.String("traceId", details.TraceID),
+			zap.Error(err),
+		)
+
+		// 内部错误信息仅在开发环境输出
+		if h.env.IsDevelopment && details.Detail == "" && e.Err != nil {
+			details.Detail = e.Err.Error()
+		}
+	}
+
+	data, marshalErr := json.Marshal(details)
+	if marshalErr != nil {
+		h.logger.Error("failed to marshal problem details", zap.Error(marshalErr))
+		c.AbortWithStatus(e.Status)
+		return
+	}
+
+	header := c.Writer.Header()
+	for key, values := range e.Headers {
+		header[key] = values
+	}
+
+	c.Abort()
+	c.Data(e.Status, problem.ContentType, data)
+}
+
+// abortWithProblem 中止请求并记录错误
+// 启用异常处理中间件时渲染为 Problem Details, 否则执行 fallback 保持原有响应
+func abortWithProblem(c *gin.Context, err *problem.Error, fallback func()) {
+	_ = c.Error(err)
+
+	if value, ok := c.Get(web.ContextExceptionHandlerKey); ok {
+		if h, ok := value.(*ExceptionHandler); ok {
+			h.render(c, err)
+			return
+		}
+	}
+
+	fallback()
+}
+
+// localizeTitle 按请求语言翻译标题, 未启用国际化或缺少翻译时使用默认标题
+func localizeTitle(c *gin.Context, e *problem.Error) string {
+	value, ok := c.Get("localizer")
+	if !ok || e.TitleID == "" {
+		return e.Title
+	}
+
+	localizer, ok := value.(*i18n.Localizer)
+	if !ok {
+		return e.Title
+	}
+
+	// 缺少翻译时 Localize 返回默认消息与 MessageNotFoundErr
+	title, _ := localizer.Localize(&i18n.LocalizeConfig{
+		DefaultMessage: &i18n.Message{ID: e.TitleID, Other: e.Title},
+	})
+	if title == "" {
+		return e.Title
+	}
+	return title
+}
+
+// traceID 依次从 traceparent、X-Request-Id 获取链路追踪 ID, 均不存在时生成
+func traceID(c *gin.Context) string {
+	if parts := strings.Split(c.GetHeader("traceparent"), "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+
+	if requestID := c.GetHeader("X-Request-Id"); requestID != "" {
+		return requestID
+	}
+
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,169 @@
+package ginx
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// HttpCaching HTTP 缓存中间件, 按路由配置写出 Cache-Control、生成 ETag 并处理条件请求
+type HttpCaching struct {
+	routes *routeLookup
+}
+
+// newHttpCaching 初始化 HTTP 缓存中间件
+func newHttpCaching(routes web.RouteTable) *HttpCaching {
+	return &HttpCaching{routes: newRouteLookup(routes)}
+}
+
+// Handle HTTP 缓存中间件处理函数
+// 配置了 ETag 的路由缓冲响应体, 处理函数未设置 ETag 时按响应体生成; GET 请求的 If-None-Match 或 If-Modified-Since 匹配时改为 304
+func (m *HttpCaching) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := m.routes.find(c)
+		if route == nil || (route.CacheControl == nil && route.ETag == web.ETagNone) {
+			c.Next()
+			return
+		}
+
+		writer := &validatorWriter{
+			ResponseWriter: c.Writer,
+			request:        c.Request,
+			etag:           route.ETag,
+			buffering:      route.ETag != web.ETagNone,
+		}
+		if route.CacheControl != nil {
+			writer.cacheControl = route.CacheControl.String()
+		}
+
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		writer.finish()
+	}
+}
+
+// validatorWriter 缓冲响应体以生成 ETag, 在写出响应头前补充 Cache-Control
+type validatorWriter struct {
+	gin.ResponseWriter
+	request      *http.Request
+	cacheControl string
+	etag         web.ETagKind
+	buffering    bool
+	buf          []byte
+	wrote        bool // 缓冲期间处理函数已写出响应头
+	committed    bool
+}
+
+func (w *validatorWriter) Write(data []byte) (int, error) {
+	if w.buffering {
+		w.wrote = true
+		w.buf = append(w.buf, data...)
+		return len(data), nil
+	}
+	w.commit()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *validatorWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *validatorWriter) WriteHeaderNow() {
+	if w.buffering {
+		w.wrote = true
+		return
+	}
+	w.commit()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *validatorWriter) Written() bool {
+	return w.wrote || w.ResponseWriter.Written()
+}
+
+// Flush 流式响应无法生成 ETag, 写出已缓冲的数据后不再缓冲
+func (w *validatorWriter) Flush() {
+	w.stopBuffering()
+	w.ResponseWriter.Flush()
+}
+
+func (w *validatorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.stopBuffering()
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *validatorWriter) stopBuffering() {
+	if !w.buffering {
+		return
+	}
+	w.buffering = false
+	w.flushBuffer()
+}
+
+// finish 处理函数返回后生成 ETag、判断条件请求并写出缓冲的响应
+// 处理函数未写出响应时(如返回错误由外层异常处理中间件渲染)不做处理
+func (w *validatorWriter) finish() {
+	if !w.buffering || !w.wrote {
+		return
+	}
+	w.buffering = false
+
+	header := w.Header()
+	if w.Status() == http.StatusOK {
+		if header.Get("ETag") == "" && w.etag != web.ETagNone {
+			header.Set("ETag", web.ComputeETag(w.buf, w.etag))
+		}
+
+		method := w.request.Method
+		if method == http.MethodGet || method == http.MethodHead {
+			lastModified, _ := http.ParseTime(header.Get("Last-Modified"))
+			if web.IsNotModified(w.request.Header, header.Get("ETag"), lastModified) {
+				w.buf = nil
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				w.ResponseWriter.WriteHeader(http.StatusNotModified)
+				w.wrote = true
+			}
+		}
+	}
+
+	w.flushBuffer()
+}
+
+// flushBuffer 写出响应头与已缓冲的数据
+func (w *validatorWriter) flushBuffer() {
+	w.commit()
+	if len(w.buf) > 0 {
+		buf := w.buf
+		w.buf = nil
+		_, _ = w.ResponseWriter.Write(buf)
+		return
+	}
+	if w.wrote {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// commit 写出响应头前补充 Cache-Control, 仅用于成功与 304 响应且处理函数未自行设置
+func (w *validatorWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+
+	if w.cacheControl == "" {
+		return
+	}
+	status := w.Status()
+	if (status < http.StatusOK || status >= http.StatusMultipleChoices) && status != http.StatusNotModified {
+		return
+	}
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", w.cacheControl)
+	}
+}
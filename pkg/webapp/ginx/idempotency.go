@@ -0,0 +1,225 @@
+package ginx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+// idempotencyStoreTimeout 请求结束后保存或释放记录的超时时间
+const idempotencyStoreTimeout = 5 * time.Second
+
+// Idempotency 幂等键中间件
+type Idempotency struct {
+	web.Idempotency
+	store  web.IdempotencyStore
+	routes *routeLookup
+	logger *zap.Logger
+}
+
+// idempotencyRecord 幂等记录, Status 为 0 表示首个请求仍在处理
+type idempotencyRecord struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// newIdempotency 初始化幂等键中间件
+func newIdempotency(idempotency web.Idempotency, store web.IdempotencyStore, routes web.RouteTable, logger *zap.Logger) *Idempotency {
+	return &Idempotency{
+		Idempotency: idempotency,
+		store:       store,
+		routes:      newRouteLookup(routes),
+		logger:      logger,
+	}
+}
+
+// Handle 幂等键中间件处理函数
+// 记录以幂等键与当前用户区分; 首个请求处理中时相同键的请求返回 409, 请求内容不同时返回 422, 处理完成后重放保存的响应;
+// 处理函数返回错误、响应为 5xx 或超过大小限制时删除记录, 允许以相同的键重试
+func (m *Idempotency) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := m.routes.find(c)
+		if route == nil || !route.Idempotent {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(m.HeaderName())
+		if key == "" {
+			if m.RequireKey() {
+				m.reject(c, problem.BadRequest("missing "+m.HeaderName()+" header"))
+				return
+			}
+			c.Next()
+			return
+		}
+		if len(key) > m.MaxKeyLength() {
+			m.reject(c, problem.BadRequest(m.HeaderName()+" header is too long"))
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c, m.MaxRequestBodySize())
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				m.reject(c, problem.PayloadTooLarge("").WithError(err))
+			} else {
+				m.reject(c, problem.BadRequest("invalid request body").WithError(err))
+			}
+			return
+		}
+
+		var user string
+		if claims := ginGetClaimsPrincipal(c); claims != nil {
+			user = claims.Subject
+		}
+		sum := sha256.Sum256([]byte(user + "\n" + key))
+		storeKey := hex.EncodeToString(sum[:])
+
+		ctx := c.Request.Context()
+		pending, _ := json.Marshal(&idempotencyRecord{Fingerprint: fingerprint})
+		data, reserved, err := m.store.Reserve(ctx, storeKey, pending, m.InFlightTimeout())
+		if err != nil {
+			m.logger.Error("failed to reserve idempotency key", zap.String("path", c.Request.URL.Path), zap.Error(err))
+			m.reject(c, problem.Internal(err))
+			return
+		}
+
+		if !reserved {
+			var record idempotencyRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				m.logger.Error("invalid idempotency record", zap.String("path", c.Request.URL.Path), zap.Error(err))
+				m.reject(c, problem.Internal(err))
+				return
+			}
+			switch {
+			case record.Fingerprint != fingerprint:
+				m.reject(c, problem.UnprocessableEntity(m.HeaderName()+" has already been used with a different request"))
+			case record.Status == 0:
+				m.reject(c, problem.Conflict("a request with the same "+m.HeaderName()+" is being processed"))
+			default:
+				replayResponse(c, &record)
+			}
+			return
+		}
+
+		// 客户端断开后请求上下文被取消, 保存与释放记录使用不随请求取消的上下文, 避免重试时重复执行
+		// panic 时同样释放记录, 由外层恢复中间件处理
+		completed := false
+		defer func() {
+			if !completed {
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+				defer cancel()
+				if err := m.store.Release(ctx, storeKey); err != nil {
+					m.logger.Warn("failed to release idempotency key", zap.String("path", c.Request.URL.Path), zap.Error(err))
+				}
+			}
+		}()
+
+		record := m.execute(c, fingerprint)
+		if record == nil {
+			return
+		}
+
+		data, err = json.Marshal(record)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyStoreTimeout)
+			err = m.store.Complete(ctx, storeKey, data, m.Expire())
+			cancel()
+		}
+		if err != nil {
+			m.logger.Error("failed to save idempotency record", zap.String("path", c.Request.URL.Path), zap.Error(err))
+			return
+		}
+		completed = true
+	}
+}
+
+// execute 执行处理函数并捕获响应, 响应不可保存时返回 nil
+func (m *Idempotency) execute(c *gin.Context, fingerprint string) *idempotencyRecord {
+	before := c.Writer.Header().Clone()
+
+	writer := &captureWriter{ResponseWriter: c.Writer, limit: m.MaxBodySize()}
+	c.Writer = writer
+	c.Next()
+	c.Writer = writer.ResponseWriter
+
+	if writer.overflow || !writer.Written() || writer.Status() >= http.StatusInternalServerError || len(c.Errors) > 0 {
+		return nil
+	}
+
+	record := &idempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      writer.Status(),
+		Header:      make(http.Header),
+		Body:        writer.body,
+	}
+	for key, values := range c.Writer.Header() {
+		if slices.Contains(uncachedHeaders, key) || slices.Equal(before[key], values) {
+			continue
+		}
+		record.Header[key] = slices.Clone(values)
+	}
+	return record
+}
+
+// reject 中止请求并输出错误
+func (m *Idempotency) reject(c *gin.Context, e *problem.Error) {
+	abortWithProblem(c, e, func() {
+		message := e.Detail
+		if message == "" {
+			message = e.Title
+		}
+		c.AbortWithStatusJSON(e.Status, gin.H{"error": message})
+	})
+}
+
+// replayResponse 重放保存的响应, 响应体与首次响应逐字节一致
+func replayResponse(c *gin.Context, record *idempotencyRecord) {
+	header := c.Writer.Header()
+	for key, values := range record.Header {
+		header[key] = values
+	}
+	header.Set("Idempotent-Replayed", "true")
+
+	c.Status(record.Status)
+	_, _ = c.Writer.Write(record.Body)
+	c.Abort()
+}
+
+// requestFingerprint 按请求方法、地址与请求体生成指纹, 读取后恢复请求体
+// 请求体超过 limit 时返回 *http.MaxBytesError, limit 为 0 表示不限制
+func requestFingerprint(c *gin.Context, limit int64) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + "\n" + c.Request.URL.RequestURI() + "\n"))
+
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		reader := c.Request.Body
+		if limit > 0 {
+			reader = http.MaxBytesReader(c.Writer, reader, limit)
+		}
+		body, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,287 @@
+package ginx
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	// listenFdsStart 继承的文件描述符起始编号(0/1/2 为标准输入输出)
+	listenFdsStart = 3
+	// envListenFds 父进程传递给子进程的监听器数量
+	envListenFds = "WORKIT_LISTEN_FDS"
+	// envListenUrls 父进程传递给子进程的监听地址, 与文件描述符一一对应
+	envListenUrls = "WORKIT_LISTEN_URLS"
+	// grpcScheme 独立端口的 gRPC 监听器
+	grpcScheme = "grpc"
+)
+
+// endpoint 监听端点
+type endpoint struct {
+	url      *url.URL
+	listener net.Listener
+}
+
+// tls 是否以 HTTPS 提供服务
+func (e *endpoint) tls() bool {
+	return e.url.Scheme == "https"
+}
+
+// grpc 是否为独立端口的 gRPC 监听器
+func (e *endpoint) grpc() bool {
+	return e.url.Scheme == grpcScheme
+}
+
+// address 实际监听地址, 端口为 0 时返回系统分配的端口
+func (e *endpoint) address() string {
+	if e.url.Scheme == "unix" {
+		return "unix://" + e.listener.Addr().String()
+	}
+	return e.url.Scheme + "://" + e.listener.Addr().String()
+}
+
+// parseUrls 解析监听地址, 支持 http://host:port、https://host:port 与 unix:///path
+func parseUrls(urls []string, tls bool) ([]*url.URL, error) {
+	parsed := make([]*url.URL, 0, len(urls))
+
+	for _, raw := range urls {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid server url %q: %w", raw, err)
+		}
+
+		switch u.Scheme {
+		case "http":
+		case "https":
+			if !tls {
+				return nil, fmt.Errorf("server url %q requires server.tls.cert_file and server.tls.key_file", raw)
+			}
+		case "unix":
+			if u.Path == "" {
+				return nil, fmt.Errorf("invalid unix socket url %q", raw)
+			}
+			parsed = append(parsed, u)
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported server url scheme %q", raw)
+		}
+
+		if u.Port() == "" {
+			return nil, fmt.Errorf("server url %q requires a port", raw)
+		}
+		parsed = append(parsed, u)
+	}
+
+	return parsed, nil
+}
+
+// listen 创建监听端点
+// 依次尝试: 父进程传递的监听器(平滑重启)、systemd socket activation、按配置地址监听
+func listen(urls []*url.URL, tls bool) ([]*endpoint, error) {
+	if endpoints, ok, err := inheritFromParent(); ok || err != nil {
+		return endpoints, err
+	}
+
+	if endpoints, ok, err := inheritFromSystemd(tls); ok || err != nil {
+		return endpoints, err
+	}
+
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, u := range urls {
+		var (
+			listener net.Listener
+			err      error
+		)
+
+		if u.Scheme == "unix" {
+			removeStaleSocket(u.Path)
+			listener, err = net.Listen("unix", u.Path)
+		} else {
+			listener, err = net.Listen("tcp", u.Host)
+		}
+
+		if err != nil {
+			closeEndpoints(endpoints)
+			return nil, fmt.Errorf("listen on %s: %w", u, err)
+		}
+
+		endpoints = append(endpoints, &endpoint{url: u, listener: listener})
+	}
+
+	return endpoints, nil
+}
+
+// removeStaleSocket 清理上次异常退出残留的 socket 文件
+// 仅当路径为 socket 且连接被拒绝(无进程监听)时删除; 其他类型的文件或仍在服务的 socket 保留, 由 Listen 返回错误
+func removeStaleSocket(path string) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		_ = conn.Close()
+		return
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		_ = os.Remove(path)
+	}
+}
+
+// inheritFromParent 继承平滑重启时父进程传递的监听器
+func inheritFromParent() ([]*endpoint, bool, error) {
+	count := os.Getenv(envListenFds)
+	if count == "" {
+		return nil, false, nil
+	}
+
+	rawUrls := strings.Split(os.Getenv(envListenUrls), ",")
+	_ = os.Unsetenv(envListenFds)
+	_ = os.Unsetenv(envListenUrls)
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n != len(rawUrls) {
+		return nil, true, fmt.Errorf("invalid %s=%q", envListenFds, count)
+	}
+
+	endpoints := make([]*endpoint, 0, n)
+	for i := 0; i < n; i++ {
+		u, err := url.Parse(rawUrls[i])
+		if err != nil {
+			closeEndpoints(endpoints)
+			return nil, true, err
+		}
+
+		listener, err := fileListener(listenFdsStart+i, u.String())
+		if err != nil {
+			closeEndpoints(endpoints)
+			return nil, true, err
+		}
+
+		endpoints = append(endpoints, &endpoint{url: u, listener: listener})
+	}
+
+	return endpoints, true, nil
+}
+
+// inheritFromSystemd 继承 systemd socket activation 传递的监听器
+// 配置了 TLS 时以 HTTPS 提供服务
+func inheritFromSystemd(tls bool) ([]*endpoint, bool, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, false, nil
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, false, nil
+	}
+
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	scheme := "http"
+	if tls {
+		scheme = "https"
+	}
+
+	endpoints := make([]*endpoint, 0, n)
+	for i := 0; i < n; i++ {
+		listener, err := fileListener(listenFdsStart+i, "systemd")
+		if err != nil {
+			closeEndpoints(endpoints)
+			return nil, true, err
+		}
+
+		u := &url.URL{Scheme: scheme, Host: listener.Addr().String()}
+		if listener.Addr().Network() == "unix" {
+			u = &url.URL{Scheme: "unix", Path: listener.Addr().String()}
+		}
+
+		endpoints = append(endpoints, &endpoint{url: u, listener: listener})
+	}
+
+	return endpoints, true, nil
+}
+
+// fileListener 由文件描述符创建监听器
+func fileListener(fd int, name string) (net.Listener, error) {
+	file := os.NewFile(uintptr(fd), name)
+	if file == nil {
+		return nil, fmt.Errorf("invalid listener fd %d", fd)
+	}
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("inherit listener fd %d: %w", fd, err)
+	}
+
+	return listener, nil
+}
+
+// startChild 启动新进程并传递监听器, 用于平滑重启
+func startChild(endpoints []*endpoint) (*os.Process, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*os.File, 0, len(endpoints))
+	urls := make([]string, 0, len(endpoints))
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	for _, e := range endpoints {
+		filer, ok := e.listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			return nil, fmt.Errorf("listener %s cannot be passed to child process", e.address())
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			return nil, err
+		}
+
+		// 父进程关闭监听器时不能删除仍由子进程使用的 socket 文件
+		if unixListener, ok := e.listener.(*net.UnixListener); ok {
+			unixListener.SetUnlinkOnClose(false)
+		}
+
+		files = append(files, file)
+		urls = append(urls, e.url.String())
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		envListenFds+"="+strconv.Itoa(len(files)),
+		envListenUrls+"="+strings.Join(urls, ","),
+	)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return cmd.Process, nil
+}
+
+// closeEndpoints 关闭已创建的监听器
+func closeEndpoints(endpoints []*endpoint) {
+	for _, e := range endpoints {
+		_ = e.listener.Close()
+	}
+}
@@ -14,7 +14,7 @@ type Localization struct {
 }
 
 // newLocalization 初始国际化中间件
-func newLocalization(provider web.Localization, logger *zap.Logger) Middleware {
+func newLocalization(provider web.Localization, logger *zap.Logger) *Localization {
 	return &Localization{
 		Localization: provider,
 		logger:       logger,
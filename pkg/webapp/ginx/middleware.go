@@ -6,3 +6,12 @@ import "github.com/gin-gonic/gin"
 type Middleware interface {
 	Handle() gin.HandlerFunc
 }
+
+// routeMetadata 返回请求匹配路由的鉴权、授权与限流元数据, 未匹配路由时返回零值
+// 元数据取自注册时的路由映射: 路由树在拆分节点时不会保留节点上的元数据
+func routeMetadata(engine *gin.Engine, c *gin.Context) gin.RouterGroup {
+	if route, ok := engine.RouterMap[c.Request.Method+":"+c.FullPath()]; ok && c.FullPath() != "" {
+		return *route
+	}
+	return gin.RouterGroup{}
+}
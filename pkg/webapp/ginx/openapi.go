@@ -0,0 +1,60 @@
+package ginx
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// UseOpenApi 提供由路由表生成的 OpenAPI 文档与文档页面, 需先通过 AddOpenApi 配置
+// 默认文档地址为 /openapi.json, 各文档地址为 /openapi/{name}.json, 文档页面为 /docs;
+// 页面的启动脚本与内置 Swagger UI 资源同源提供于 /docs/ui.js 与 /docs/assets/, 以上路由允许匿名访问
+func (a *WebApplication) UseOpenApi() web.Application {
+	a.routeRegistrations = append(a.routeRegistrations, func(engine *gin.Engine, openApi web.OpenApi) {
+		documents := openApi.Documents()
+
+		engine.GET(openApi.Path()+".json", func(c *gin.Context) {
+			writeOpenApiDocument(c, openApi, documents[0])
+		}).WithAllowAnonymous()
+
+		engine.GET(openApi.Path()+"/:document", func(c *gin.Context) {
+			name, ok := strings.CutSuffix(c.Param("document"), ".json")
+			if !ok {
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+			writeOpenApiDocument(c, openApi, name)
+		}).WithAllowAnonymous()
+
+		if uiPath := openApi.UIPath(); uiPath != "" {
+			page := openApi.UIPage()
+			engine.GET(uiPath, func(c *gin.Context) {
+				c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+			}).WithAllowAnonymous()
+
+			script := openApi.UIScript()
+			engine.GET(uiPath+web.OpenApiUIScriptPath, func(c *gin.Context) {
+				c.Data(http.StatusOK, "text/javascript; charset=utf-8", script)
+			}).WithAllowAnonymous()
+
+			if files := openApi.UIFiles(); files != nil {
+				assetsPath := uiPath + web.OpenApiUIAssetsPath
+				fileServer := http.StripPrefix(assetsPath, http.FileServer(files))
+				engine.GET(assetsPath+"/*filepath", gin.WrapH(fileServer)).WithAllowAnonymous()
+			}
+		}
+	})
+	return a
+}
+
+// writeOpenApiDocument 写出 OpenAPI 文档
+func writeOpenApiDocument(c *gin.Context, openApi web.OpenApi, name string) {
+	data, ok := openApi.Document(name)
+	if !ok {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.Data(http.StatusOK, "application/json", data)
+}
@@ -0,0 +1,268 @@
+package ginx
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// uncachedHeaders 不写入缓存的响应头, 由外层中间件或 net/http 在每次响应时生成
+var uncachedHeaders = []string{"Content-Length", "Content-Encoding", "Date", "Set-Cookie"}
+
+// errOutputCachePanic 首个请求的处理函数发生 panic, 等待中的请求自行处理
+var errOutputCachePanic = errors.New("output cache handler panicked")
+
+// OutputCache 输出缓存中间件
+type OutputCache struct {
+	web.OutputCache
+	store  web.OutputCacheStore
+	routes *routeLookup
+	logger *zap.Logger
+	group  singleflight.Group
+}
+
+// cachedResponse 缓存的响应
+type cachedResponse struct {
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	Created time.Time   `json:"created"`
+}
+
+// newOutputCache 初始化输出缓存中间件
+func newOutputCache(cache web.OutputCache, store web.OutputCacheStore, routes web.RouteTable, logger *zap.Logger) *OutputCache {
+	return &OutputCache{
+		OutputCache: cache,
+		store:       store,
+		routes:      newRouteLookup(routes),
+		logger:      logger,
+	}
+}
+
+// Handle 输出缓存中间件处理函数
+// 仅缓存配置了输出缓存的 GET 路由中 200 且未设置 Cookie 的响应; 同一缓存键的并发未命中请求只执行一次处理函数
+func (m *OutputCache) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		route := m.routes.find(c)
+		if route == nil || !route.OutputCache {
+			c.Next()
+			return
+		}
+
+		policy, ok := m.Policy(route.OutputCachePolicy)
+		if !ok {
+			m.logger.Error("output cache policy not found",
+				zap.String("path", route.Path),
+				zap.String("policy", route.OutputCachePolicy))
+			c.Next()
+			return
+		}
+
+		// 未按用户区分缓存时不缓存已认证的请求, 避免响应泄露给其他用户
+		var user string
+		if claims := ginGetClaimsPrincipal(c); claims != nil {
+			user = claims.Subject
+		}
+		if !policy.VaryByUser && (user != "" || c.GetHeader("Authorization") != "") {
+			c.Next()
+			return
+		}
+
+		key := cacheKey(c, policy, user)
+		ctx := c.Request.Context()
+
+		data, err := m.store.Get(ctx, key)
+		if err != nil {
+			m.logger.Warn("failed to read output cache", zap.String("path", c.Request.URL.Path), zap.Error(err))
+		}
+		if len(data) > 0 {
+			var entry cachedResponse
+			if err := json.Unmarshal(data, &entry); err == nil {
+				writeCachedResponse(c, &entry)
+				return
+			}
+		}
+
+		leader := false
+		var recovered any
+		result, _, _ := m.group.Do(key, func() (_ any, err error) {
+			leader = true
+
+			// 处理函数 panic 时在 Do 内恢复, 避免 singleflight 在存在等待者时另起 goroutine 重新 panic
+			defer func() {
+				if r := recover(); r != nil {
+					recovered = r
+					err = errOutputCachePanic
+				}
+			}()
+
+			entry := m.execute(c)
+			if entry == nil {
+				return nil, nil
+			}
+
+			if data, err := json.Marshal(entry); err == nil {
+				if err := m.store.Set(ctx, key, data, policy.Tags, policy.Expire); err != nil {
+					m.logger.Warn("failed to write output cache", zap.String("path", c.Request.URL.Path), zap.Error(err))
+				}
+			}
+			return entry, nil
+		})
+		if leader {
+			// 仅在首个请求所在的 goroutine 中重新 panic, 交由上层 Recovery 处理
+			if recovered != nil {
+				panic(recovered)
+			}
+			return
+		}
+
+		// 等待中的请求复用首个请求的响应, 响应不可缓存时自行处理
+		entry, _ := result.(*cachedResponse)
+		if entry == nil {
+			c.Next()
+			return
+		}
+		writeCachedResponse(c, entry)
+	}
+}
+
+// execute 执行处理函数并捕获响应, 响应不可缓存时返回 nil
+func (m *OutputCache) execute(c *gin.Context) *cachedResponse {
+	before := c.Writer.Header().Clone()
+
+	writer := &captureWriter{ResponseWriter: c.Writer, limit: m.MaxBodySize()}
+	c.Writer = writer
+	defer func() { c.Writer = writer.ResponseWriter }()
+	c.Next()
+
+	header := c.Writer.Header()
+	if writer.overflow || !writer.Written() || writer.Status() != http.StatusOK || len(c.Errors) > 0 ||
+		header.Get("Set-Cookie") != "" {
+		return nil
+	}
+
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	if strings.Contains(cacheControl, "no-store") || strings.Contains(cacheControl, "private") {
+		return nil
+	}
+
+	// 只缓存处理过程中设置的响应头
+	entry := &cachedResponse{
+		Status:  writer.Status(),
+		Header:  make(http.Header),
+		Body:    writer.body,
+		Created: time.Now(),
+	}
+	for key, values := range header {
+		if slices.Contains(uncachedHeaders, key) || slices.Equal(before[key], values) {
+			continue
+		}
+		entry.Header[key] = slices.Clone(values)
+	}
+
+	return entry
+}
+
+// writeCachedResponse 写出缓存的响应
+func writeCachedResponse(c *gin.Context, entry *cachedResponse) {
+	header := c.Writer.Header()
+	for key, values := range entry.Header {
+		header[key] = values
+	}
+	header.Set("Age", strconv.Itoa(int(time.Since(entry.Created).Seconds())))
+
+	c.Status(entry.Status)
+	_, _ = c.Writer.Write(entry.Body)
+	c.Abort()
+}
+
+// cacheKey 按策略生成缓存键
+func cacheKey(c *gin.Context, policy *web.OutputCachePolicy, user string) string {
+	var b strings.Builder
+	b.WriteString(c.Request.Host)
+	b.WriteByte('\n')
+	b.WriteString(c.Request.URL.Path)
+
+	query := c.Request.URL.Query()
+	names := policy.VaryByQuery
+	if slices.Contains(names, "*") {
+		names = make([]string, 0, len(query))
+		for name := range query {
+			names = append(names, name)
+		}
+	}
+	names = slices.Clone(names)
+	slices.Sort(names)
+	for _, name := range names {
+		b.WriteString("\nq:" + name + "=" + strings.Join(query[name], ","))
+	}
+
+	for _, name := range policy.VaryByHeader {
+		b.WriteString("\nh:" + http.CanonicalHeaderKey(name) + "=" + strings.Join(c.Request.Header.Values(name), ","))
+	}
+	for _, name := range policy.VaryByRouteValue {
+		b.WriteString("\nr:" + name + "=" + c.Param(name))
+	}
+	if policy.VaryByUser {
+		b.WriteString("\nu:" + user)
+	}
+	if version := c.GetString(web.ContextApiVersionKey); version != "" {
+		b.WriteString("\nv:" + version)
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// captureWriter 写出响应的同时缓存响应体, 超出大小限制后停止缓存
+type captureWriter struct {
+	gin.ResponseWriter
+	body     []byte
+	limit    int64
+	overflow bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.overflow = true
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *captureWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.limit > 0 && int64(len(w.body)+len(data)) > w.limit {
+		w.overflow = true
+		w.body = nil
+		return
+	}
+	w.body = append(w.body, data...)
+}
@@ -3,21 +3,24 @@ package ginx
 import (
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 	"go.uber.org/zap"
 )
 
+// contextRateLimitedKey 请求已经过限流中间件
+const contextRateLimitedKey = "rateLimited"
+
 type RateLimitr struct {
 	*gin.Engine
 	web.Router
 	logger *zap.Logger
 }
 
-func newRateLimiter(engine *gin.Engine, router web.Router, logger *zap.Logger) Middleware {
+func newRateLimiter(engine *gin.Engine, router web.Router, logger *zap.Logger) *RateLimitr {
 	return &RateLimitr{
 		Router: router,
 		logger: logger,
@@ -30,72 +33,55 @@ func (m *RateLimitr) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := c.Request.Method
 		path := c.Request.URL.Path
+		key := c.ClientIP()
+
+		nodeValue := routeMetadata(m.Engine, c)
+
+		// 标记请求已经过限流, gRPC 转码路由据此跳过 gRPC 限流拦截器
+		c.Set(contextRateLimitedKey, true)
 
-		nodeValue := m.GetNodeValue(c)
-		// 获取路由对应的限流器
-		limiters := nodeValue.LimitersPolices
+		// 全局限流器始终生效, 随后依次应用路由限流器
+		chain := ratelimit.NewChain(m.Router, nodeValue.LimitersPolices...)
 
-		// 如果没有配置路由策略，才使用默认策略
-		if len(limiters) == 0 && m.GlobalRatelimit() != "" {
-			limiters = append(limiters, m.GlobalRatelimit())
+		for _, policy := range chain.Missing() {
+			m.logger.Error("rate limit handler not found",
+				zap.String("path", path),
+				zap.String("method", method),
+				zap.String("policy", policy),
+				zap.String("clientIP", key))
 		}
 
-		if len(limiters) == 0 {
+		if chain.Empty() {
 			c.Next()
 			return
 		}
 
-		key := c.ClientIP()
-		var maxRetryAfter time.Duration
-		blocked := false
-
-		for _, limiter := range limiters {
-			handler, ok := m.RateLimiter(limiter)
-			if !ok {
-				m.logger.Error("rate limit handler not found",
-					zap.String("path", path),
-					zap.String("method", method),
-					zap.String("policy", limiter),
-					zap.String("clientIP", key))
-				continue
-			}
+		lease, rejection := chain.TryAcquire(key)
+		if rejection != nil {
+			m.logger.Info("rate limit exceeded",
+				zap.String("path", path),
+				zap.String("method", method),
+				zap.String("policy", rejection.Policy),
+				zap.String("clientIP", key),
+				zap.Duration("retryAfter", rejection.RetryAfter))
 
-			allowed, retryAfter := handler.TryAcquire(key)
-			if !allowed {
-				blocked = true
-				if retryAfter > maxRetryAfter {
-					maxRetryAfter = retryAfter
-				}
-				m.logger.Info("rate limit exceeded",
-					zap.String("path", path),
-					zap.String("method", method),
-					zap.String("policy", limiter),
-					zap.String("clientIP", key),
-					zap.Duration("retryAfter", retryAfter))
-			}
-		}
-
-		if blocked {
 			// Retry-After 按规范需要返回秒数整数
-			c.Header("Retry-After", strconv.Itoa(int(maxRetryAfter.Seconds())))
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"code":       429,
-				"message":    "Too Many Requests",
-				"retryAfter": int(maxRetryAfter.Seconds()),
+			retryAfter := int(rejection.RetryAfter.Seconds())
+			abortWithProblem(c, problem.TooManyRequests(retryAfter), func() {
+				c.Header("Retry-After", strconv.Itoa(retryAfter))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"code":       429,
+					"message":    "Too Many Requests",
+					"retryAfter": retryAfter,
+				})
 			})
 			return
 		}
 
+		// 并发限流需要在请求结束后释放资源
+		defer lease.Release()
+
 		// 正常执行下游 handler
 		c.Next()
-
-		// 并发限流需要在请求结束后释放资源
-		for _, limiter := range limiters {
-			if handler, ok := m.RateLimiter(limiter); ok && handler != nil {
-				if cl, ok := handler.(*ratelimit.ConcurrencyLimiter); ok {
-					cl.Release(key)
-				}
-			}
-		}
 	}
 }
@@ -1,10 +1,12 @@
 package ginx
 
 import (
+	"fmt"
 	"net/http"
 	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
 	"go.uber.org/zap"
 )
 
@@ -20,7 +22,13 @@ func newRecoveryWithZap(logger *zap.Logger) gin.HandlerFunc {
 					zap.String("ip", c.ClientIP()),
 					zap.ByteString("stack", debug.Stack()),
 				)
-				c.AbortWithStatus(http.StatusInternalServerError)
+				panicErr, ok := err.(error)
+				if !ok {
+					panicErr = fmt.Errorf("%v", err)
+				}
+				abortWithProblem(c, problem.Internal(panicErr), func() {
+					c.AbortWithStatus(http.StatusInternalServerError)
+				})
 			}
 		}()
 		c.Next()
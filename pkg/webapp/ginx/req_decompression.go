@@ -1,21 +1,27 @@
 package ginx
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 	"go.uber.org/zap"
 )
 
-// Authorize 授权中间件
+// ratioCheckThreshold 解压数据达到该大小后才校验压缩比, 避免小请求体因压缩头开销被误判
+const ratioCheckThreshold = 1 << 20
+
+// ReqDecompression 请求解压中间件
 type ReqDecompression struct {
 	web.ReqDecompressor
 	logger *zap.Logger
 }
 
-// newAuthorize 初始化授权中间件
+// newDecompression 初始化请求解压中间件
 func newDecompression(dec web.ReqDecompressor, logger *zap.Logger) *ReqDecompression {
 	return &ReqDecompression{
 		ReqDecompressor: dec,
@@ -23,44 +29,161 @@ func newDecompression(dec web.ReqDecompressor, logger *zap.Logger) *ReqDecompres
 	}
 }
 
-// Handle 授权中间件处理函数
+// Handle 请求解压中间件处理函数
+// 支持多层编码(如 gzip, br), 按编码顺序的逆序解压
+// 请求体以流的形式解压, 默认在读取时校验解压后的大小与压缩比, 超出限制时读取返回 *http.MaxBytesError,
+// 由错误映射转换为 413; 是否将请求体读入内存由处理函数决定
 func (m *ReqDecompression) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		encoding := strings.ToLower(c.GetHeader("Content-Encoding"))
-		if encoding == "" {
+		encodings := parseContentEncoding(c.GetHeader("Content-Encoding"))
+		if len(encodings) == 0 {
 			// 没有压缩，直接继续
+			c.Request.Header.Del("Content-Encoding")
 			c.Next()
 			return
 		}
 
-		provider, ok := m.ReqDecompressor.Decompression(encoding)
-		if !ok {
-			m.logger.Warn("unsupported content-encoding", zap.String("encoding", encoding))
-			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
-				"error": "unsupported content-encoding",
-			})
-			return
+		providers := make([]web.ReqDecompression, 0, len(encodings))
+		for _, encoding := range encodings {
+			provider, ok := m.ReqDecompressor.Decompression(encoding)
+			if !ok {
+				m.logger.Warn("unsupported content-encoding", zap.String("encoding", encoding))
+				abortWithProblem(c, problem.UnsupportedMediaType("unsupported content-encoding"), func() {
+					c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+						"error": "unsupported content-encoding",
+					})
+				})
+				return
+			}
+			providers = append(providers, provider)
 		}
 
-		reader, err := provider.Decompression(c.Request.Body)
-		if err != nil {
-			m.logger.Error("failed to create decompression stream",
-				zap.String("encoding", encoding),
-				zap.Error(err),
-			)
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "invalid compressed body",
-			})
-			return
-		}
+		original := c.Request.Body
+		compressed := &countingReader{r: original}
+		body := &decompressedBody{closers: []io.Closer{original}}
 
-		// 替换请求体为解压流
-		c.Request.Body = reader
+		var reader io.Reader = compressed
+		for i := len(providers) - 1; i >= 0; i-- {
+			decoder, err := providers[i].Decompression(reader)
+			if err != nil {
+				_ = body.Close()
+				m.logger.Warn("failed to create decompression stream",
+					zap.String("encoding", providers[i].Type()),
+					zap.Error(err),
+				)
+				abortWithProblem(c, problem.BadRequest("invalid compressed body"), func() {
+					c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+						"error": "invalid compressed body",
+					})
+				})
+				return
+			}
+			body.closers = append(body.closers, decoder)
+			reader = decoder
+		}
+		body.Reader = reader
 
 		// 删除 Content-Encoding 头，避免后续 handler 误判
 		c.Request.Header.Del("Content-Encoding")
 
-		// 继续执行
+		// 解压后长度未知
+		c.Request.Header.Del("Content-Length")
+		c.Request.ContentLength = -1
+
+		if m.LimitsDisabled(c.FullPath()) {
+			c.Request.Body = body
+			c.Next()
+			return
+		}
+
+		c.Request.Body = &limitedReader{
+			ReadCloser: body,
+			compressed: compressed,
+			maxSize:    m.MaxDecompressedSize(),
+			maxRatio:   m.MaxRatio(),
+			logger:     m.logger,
+			path:       c.Request.URL.Path,
+		}
+
 		c.Next()
 	}
 }
+
+// parseContentEncoding 解析 Content-Encoding, 忽略 identity
+func parseContentEncoding(header string) []string {
+	var encodings []string
+	for _, encoding := range strings.Split(header, ",") {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+		encodings = append(encodings, encoding)
+	}
+	return encodings
+}
+
+// countingReader 统计已读取的压缩数据大小
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// limitedReader 限制解压后的数据大小与压缩比, 超出限制后读取始终返回 *http.MaxBytesError
+type limitedReader struct {
+	io.ReadCloser
+	compressed *countingReader
+	maxSize    int64
+	maxRatio   float64
+	n          int64
+	err        error
+	logger     *zap.Logger
+	path       string
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+
+	switch {
+	case r.maxSize > 0 && r.n > r.maxSize:
+		r.err = &http.MaxBytesError{Limit: r.maxSize}
+	case r.maxRatio > 0 && r.n > ratioCheckThreshold && float64(r.n) > float64(r.compressed.n)*r.maxRatio:
+		r.err = &http.MaxBytesError{Limit: int64(float64(r.compressed.n) * r.maxRatio)}
+	default:
+		return n, err
+	}
+
+	r.logger.Warn("decompressed request body too large",
+		zap.String("path", r.path),
+		zap.Int64("compressed", r.compressed.n),
+		zap.Int64("decompressed", r.n),
+	)
+	return n, r.err
+}
+
+// decompressedBody 解压后的请求体, 关闭时依次关闭各层解压流与原始请求体
+type decompressedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	var errs []error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if err := b.closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.closers = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,229 @@
+package ginx
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+// RespCompression 响应压缩中间件
+type RespCompression struct {
+	web.RespCompressor
+	logger *zap.Logger
+}
+
+// newRespCompression 初始化响应压缩中间件
+func newRespCompression(compressor web.RespCompressor, logger *zap.Logger) *RespCompression {
+	return &RespCompression{
+		RespCompressor: compressor,
+		logger:         logger,
+	}
+}
+
+// Handle 响应压缩中间件处理函数
+func (m *RespCompression) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// HEAD、Range 与协议升级请求不压缩
+		if c.Request.Method == http.MethodHead || c.GetHeader("Range") != "" || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		provider, ok := m.Negotiate(c.GetHeader("Accept-Encoding"))
+		if !ok {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			compressor:     m.RespCompressor,
+			provider:       provider,
+			logger:         m.logger,
+		}
+		c.Writer = writer
+
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// compressWriter 压缩响应写入器
+// 响应体先缓冲至最小压缩大小再决定是否压缩; 调用 Flush 时立即决定, 以支持流式响应
+type compressWriter struct {
+	gin.ResponseWriter
+	compressor web.RespCompressor
+	provider   web.RespCompression
+	logger     *zap.Logger
+	status     int
+	buf        []byte
+	decided    bool
+	encoder    io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(len(w.buf) >= w.compressor.MinSize())
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		if len(w.buf) >= w.compressor.MinSize() {
+			if err := w.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(data), nil
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 刷新已压缩的数据, 流式响应不受最小压缩大小限制
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			w.logger.Warn("failed to flush compressed response", zap.Error(err))
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Status() int {
+	if !w.decided && w.status != 0 {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *compressWriter) Written() bool {
+	return w.status != 0 || len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// finish 请求处理结束后写出剩余数据并关闭压缩流
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if err := w.decide(len(w.buf) >= w.compressor.MinSize()); err != nil {
+			return
+		}
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Close(); err != nil {
+			w.logger.Warn("failed to close compressed response", zap.Error(err))
+		}
+		w.encoder = nil
+	}
+}
+
+// decide 决定是否压缩, 写出响应头与已缓冲的数据
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+
+	header := w.Header()
+
+	// 未设置 Content-Type 时按原始内容探测, 否则 net/http 会对压缩后的数据进行探测
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if compress && w.shouldCompress() {
+		encoder, err := w.provider.Compression(w.ResponseWriter, w.compressor.Level())
+		if err != nil {
+			w.logger.Error("failed to create compression stream",
+				zap.String("encoding", w.provider.Type()),
+				zap.Error(err),
+			)
+		} else {
+			header.Del("Content-Length")
+			header.Set("Content-Encoding", w.provider.Type())
+			// 强校验器须随内容编码变化, 压缩后的响应与原始响应不再逐字节一致, 改为弱校验器
+			if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+				header.Set("ETag", "W/"+etag)
+			}
+			w.encoder = encoder
+		}
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	buf := w.buf
+	w.buf = nil
+
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// shouldCompress 响应是否需要压缩, 已编码的内容、无响应体的状态码与不允许的类型不压缩
+func (w *compressWriter) shouldCompress() bool {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+
+	return w.compressor.Compressible(header.Get("Content-Type"))
+}
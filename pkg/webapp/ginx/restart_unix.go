@@ -0,0 +1,11 @@
+//go:build !windows
+
+package ginx
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignals 触发平滑重启的信号
+var restartSignals = []os.Signal{syscall.SIGUSR2}
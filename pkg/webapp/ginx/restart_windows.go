@@ -0,0 +1,8 @@
+//go:build windows
+
+package ginx
+
+import "os"
+
+// restartSignals Windows 不支持向子进程传递监听器, 不启用平滑重启
+var restartSignals []os.Signal
@@ -0,0 +1,29 @@
+package ginx
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// RegisterRoute 将路由配置及其元数据注册到 gin, 配置了超时的路由在处理函数之前应用 RouteTimeout
+func RegisterRoute(engine *gin.Engine, route *web.RouteConfig, handler gin.HandlerFunc) {
+	handlers := make([]gin.HandlerFunc, 0, 2)
+	if route.Timeout > 0 {
+		handlers = append(handlers, RouteTimeout(route.Timeout))
+	}
+	handlers = append(handlers, handler)
+
+	routes := engine.Handle(string(route.Method), route.Path, handlers...)
+	if len(route.Schemes) > 0 {
+		routes = routes.WithAuthSchemes(route.Schemes...)
+	}
+	if len(route.Policies) > 0 {
+		routes = routes.WithAuthzPolicies(route.Policies...)
+	}
+	if len(route.RateLimiter) > 0 {
+		routes = routes.WithRateLimit(route.RateLimiter...)
+	}
+	if route.AllowAnonymous {
+		routes.WithAllowAnonymous()
+	}
+}
@@ -0,0 +1,214 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// routesDiagnosticsPath 路由诊断端点
+const routesDiagnosticsPath = "/_routes"
+
+// routeInfo 路由诊断信息, 鉴权方案与限流器为实际生效的配置
+type routeInfo struct {
+	Method         string   `json:"method"`
+	Path           string   `json:"path"`
+	Schemes        []string `json:"schemes"`
+	Policies       []string `json:"policies"`
+	RateLimiters   []string `json:"rateLimiters"`
+	AllowAnonymous bool     `json:"allowAnonymous"`
+	Timeout        string   `json:"timeout,omitempty"`
+	ApiVersions    []string `json:"apiVersions,omitempty"`
+	Name           string   `json:"name,omitempty"`
+}
+
+// UseRouteDiagnostics 启动时以日志输出路由表, 开发环境下提供匿名访问的 /_routes 端点
+func (a *WebApplication) UseRouteDiagnostics() web.Application {
+	a.AppendContainer(fx.Invoke(func(lc fx.Lifecycle, router web.Router, logger *zap.Logger) {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				for _, info := range describeRoutes(a.Routes(), router) {
+					logger.Info("route",
+						zap.String("method", info.Method),
+						zap.String("path", info.Path),
+						zap.Strings("schemes", info.Schemes),
+						zap.Strings("policies", info.Policies),
+						zap.Strings("limiters", info.RateLimiters),
+						zap.Bool("anonymous", info.AllowAnonymous),
+					)
+				}
+				return nil
+			},
+		})
+	}))
+
+	if a.env.IsDevelopment {
+		a.routeGroups.MapGroup("/").
+			WithAllowAnonymous().
+			ExcludeFromDescription().
+			MapGet(routesDiagnosticsPath, func(router web.Router) gin.HandlerFunc {
+				return func(c *gin.Context) {
+					c.JSON(http.StatusOK, describeRoutes(a.Routes(), router))
+				}
+			}).
+			DisableRateLimiting()
+	}
+
+	return a
+}
+
+// routeValidationDeps 路由校验的依赖, 输出缓存、幂等性与跨域为可选功能
+type routeValidationDeps struct {
+	fx.In
+	Router      web.Router
+	Logger      *zap.Logger
+	OutputCache web.OutputCache `optional:"true"`
+	Idempotency web.Idempotency `optional:"true"`
+	Cors        web.Cors        `optional:"true"`
+}
+
+// validateRoutes 校验默认授权策略、路由与 gRPC 方法规则引用的鉴权方案、授权策略、限流器, 以及路由引用的输出缓存策略、跨域策略与幂等性配置均已注册
+func validateRoutes(routes []*web.RouteConfig, deps routeValidationDeps) error {
+	var errs []error
+	router, outputCache := deps.Router, deps.OutputCache
+
+	if scheme := router.GlobalScheme(); scheme != "" {
+		if _, ok := router.Authenticate(scheme); !ok {
+			errs = append(errs, fmt.Errorf("default authentication scheme %q not found", scheme))
+		}
+	}
+	if policy := router.GlobalPolicy(); policy != "" {
+		if _, ok := router.Authorize(policy); !ok {
+			errs = append(errs, fmt.Errorf("default authorization policy %q not found", policy))
+		}
+	}
+	global := router.GlobalRatelimit()
+	if global != "" {
+		for _, limiter := range ratelimit.NewChain(router).Missing() {
+			errs = append(errs, fmt.Errorf("global rate limiter %q not found", limiter))
+		}
+	}
+
+	for _, route := range routes {
+		for _, scheme := range route.Schemes {
+			if _, ok := router.Authenticate(scheme); !ok {
+				errs = append(errs, fmt.Errorf("route %s %s: authentication scheme %q not found", route.Method, route.Path, scheme))
+			}
+		}
+		for _, policy := range route.Policies {
+			if _, ok := router.Authorize(policy); !ok {
+				errs = append(errs, fmt.Errorf("route %s %s: authorization policy %q not found", route.Method, route.Path, policy))
+			}
+		}
+		for _, limiter := range ratelimit.NewChain(router, route.RateLimiter...).Missing() {
+			if limiter == global {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("route %s %s: rate limiter %q not found", route.Method, route.Path, limiter))
+		}
+		if route.OutputCache {
+			if outputCache == nil {
+				errs = append(errs, fmt.Errorf("route %s %s: output cache is not configured", route.Method, route.Path))
+			} else if _, ok := outputCache.Policy(route.OutputCachePolicy); !ok {
+				errs = append(errs, fmt.Errorf("route %s %s: output cache policy %q not found", route.Method, route.Path, route.OutputCachePolicy))
+			}
+		}
+		if route.CorsPolicy != "" {
+			if deps.Cors == nil {
+				errs = append(errs, fmt.Errorf("route %s %s: cors is not configured", route.Method, route.Path))
+			} else if _, ok := deps.Cors.Policy(route.CorsPolicy); !ok {
+				errs = append(errs, fmt.Errorf("route %s %s: cors policy %q not found", route.Method, route.Path, route.CorsPolicy))
+			}
+		}
+		if route.Idempotent && deps.Idempotency == nil {
+			errs = append(errs, fmt.Errorf("route %s %s: idempotency is not configured", route.Method, route.Path))
+		}
+	}
+
+	errs = append(errs, validateGrpcMethods(router, global)...)
+
+	return errors.Join(errs...)
+}
+
+// validateGrpcMethods 校验 gRPC 方法规则引用的鉴权方案、授权策略与限流器均已注册
+func validateGrpcMethods(router web.Router, global string) []error {
+	var errs []error
+	rules := router.GrpcMethodRules()
+
+	for _, rule := range rules.Schemes {
+		for _, scheme := range rule.Values {
+			if _, ok := router.Authenticate(scheme); !ok {
+				errs = append(errs, fmt.Errorf("grpc method %s: authentication scheme %q not found", rule.Path, scheme))
+			}
+		}
+	}
+	for _, rule := range rules.Policies {
+		for _, policy := range rule.Values {
+			if _, ok := router.Authorize(policy); !ok {
+				errs = append(errs, fmt.Errorf("grpc method %s: authorization policy %q not found", rule.Path, policy))
+			}
+		}
+	}
+	for _, rule := range rules.RateLimits {
+		for _, limiter := range ratelimit.NewChain(router, rule.Values...).Missing() {
+			if limiter == global {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("grpc method %s: rate limiter %q not found", rule.Path, limiter))
+		}
+	}
+
+	return errs
+}
+
+// describeRoutes 生成路由诊断信息
+func describeRoutes(routes []*web.RouteConfig, router web.Router) []routeInfo {
+	infos := make([]routeInfo, 0, len(routes))
+	for _, route := range routes {
+		info := routeInfo{
+			Method:         string(route.Method),
+			Path:           route.Path,
+			Schemes:        []string{},
+			Policies:       slices.Clone(route.Policies),
+			RateLimiters:   []string{},
+			AllowAnonymous: route.AllowAnonymous,
+			ApiVersions:    append(slices.Clone(route.ApiVersions), route.DeprecatedApiVersions...),
+			Name:           route.Name,
+		}
+		if info.Policies == nil {
+			info.Policies = []string{}
+		}
+		if route.Timeout > 0 {
+			info.Timeout = route.Timeout.String()
+		}
+
+		if !route.AllowAnonymous {
+			info.Schemes = append(info.Schemes, route.Schemes...)
+			if scheme := router.GlobalScheme(); len(info.Schemes) == 0 && scheme != "" {
+				info.Schemes = append(info.Schemes, scheme)
+			}
+		}
+
+		if !slices.Contains(route.RateLimiter, web.RateLimitDisabled) {
+			if limiter := router.GlobalRatelimit(); limiter != "" {
+				info.RateLimiters = append(info.RateLimiters, limiter)
+			}
+			for _, limiter := range route.RateLimiter {
+				if !slices.Contains(info.RateLimiters, limiter) {
+					info.RateLimiters = append(info.RateLimiters, limiter)
+				}
+			}
+		}
+
+		infos = append(infos, info)
+	}
+	return infos
+}
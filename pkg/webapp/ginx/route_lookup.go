@@ -0,0 +1,107 @@
+package ginx
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// routeLookup 按请求匹配的路由模板查找路由配置
+// 路由表在应用启动后才完整, 首次查找时建立索引
+type routeLookup struct {
+	routes web.RouteTable
+	once   sync.Once
+	byKey  map[string]*web.RouteConfig
+	list   []*web.RouteConfig
+}
+
+func newRouteLookup(routes web.RouteTable) *routeLookup {
+	return &routeLookup{routes: routes}
+}
+
+// find 返回当前请求匹配的路由配置, 未匹配时返回 nil
+func (l *routeLookup) find(c *gin.Context) *web.RouteConfig {
+	l.init()
+	return l.byKey[c.Request.Method+":"+c.FullPath()]
+}
+
+// match 按请求方法与路径匹配路由模板, 用于查找预检请求实际请求的路由
+// 与 gin 一致, 逐段比较时静态段优先于参数段, 参数段优先于通配段
+func (l *routeLookup) match(method, path string) *web.RouteConfig {
+	l.init()
+	if route, ok := l.byKey[method+":"+path]; ok {
+		return route
+	}
+	for _, route := range l.list {
+		if string(route.Method) == method && matchPath(route.Path, path) {
+			return route
+		}
+	}
+	return nil
+}
+
+func (l *routeLookup) init() {
+	l.once.Do(func() {
+		l.list = slices.Clone(l.routes.Routes())
+		slices.SortStableFunc(l.list, func(a, b *web.RouteConfig) int {
+			return compareTemplates(a.Path, b.Path)
+		})
+		l.byKey = make(map[string]*web.RouteConfig, len(l.list))
+		for _, route := range l.list {
+			l.byKey[string(route.Method)+":"+route.Path] = route
+		}
+	})
+}
+
+// compareTemplates 按匹配优先级比较路由模板, 逐段比较静态段、参数段与通配段
+func compareTemplates(a, b string) int {
+	aSegments := strings.Split(strings.Trim(a, "/"), "/")
+	bSegments := strings.Split(strings.Trim(b, "/"), "/")
+
+	for i := 0; i < min(len(aSegments), len(bSegments)); i++ {
+		if diff := segmentRank(aSegments[i]) - segmentRank(bSegments[i]); diff != 0 {
+			return diff
+		}
+	}
+	return 0
+}
+
+// segmentRank 路由模板段的匹配优先级, 值越小越优先
+func segmentRank(segment string) int {
+	switch {
+	case strings.HasPrefix(segment, "*"):
+		return 2
+	case strings.HasPrefix(segment, ":"):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// matchPath 路径是否匹配路由模板, 支持 :param 与 *catchAll
+func matchPath(template, path string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range templateSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(templateSegments) == len(pathSegments)
+}
@@ -0,0 +1,123 @@
+package ginx
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+var (
+	handlerFuncType = reflect.TypeOf(gin.HandlerFunc(nil))
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// MapGroup 创建路由分组, 分组及其路由在应用启动时通过容器注册
+func (a *WebApplication) MapGroup(prefix string) *web.GroupRouteConfig {
+	return a.routeGroups.MapGroup(prefix)
+}
+
+// Routes 返回路由表, 包含路由分组与 MapRoute 注册的全部路由, 应用启动前返回空
+func (a *WebApplication) Routes() []*web.RouteConfig {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return slices.Clone(a.routeTable)
+}
+
+// makeRouteInvoke 生成路由注册函数
+// 处理函数为构造函数时, 其参数由容器注入, 返回值作为路由的处理函数
+func makeRouteInvoke(route *web.RouteConfig) any {
+	if handler, ok := asHandlerFunc(route.Handler); ok {
+		return func(engine *gin.Engine) {
+			RegisterRoute(engine, route, handler)
+		}
+	}
+
+	constructorType := reflect.TypeOf(route.Handler)
+	if constructorType == nil || constructorType.Kind() != reflect.Func ||
+		constructorType.NumOut() == 0 || constructorType.NumOut() > 2 ||
+		!constructorType.Out(0).ConvertibleTo(handlerFuncType) ||
+		(constructorType.NumOut() == 2 && constructorType.Out(1) != errorType) {
+		panic(fmt.Sprintf("route %s %s: handler must be gin.HandlerFunc or a constructor returning gin.HandlerFunc",
+			route.Method, route.Path))
+	}
+
+	in := make([]reflect.Type, 0, constructorType.NumIn()+1)
+	for i := 0; i < constructorType.NumIn(); i++ {
+		in = append(in, constructorType.In(i))
+	}
+	in = append(in, reflect.TypeOf((*gin.Engine)(nil)))
+
+	fnType := reflect.FuncOf(in, []reflect.Type{errorType}, false)
+	constructor := reflect.ValueOf(route.Handler)
+
+	fn := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		out := constructor.Call(args[:len(args)-1])
+		if len(out) == 2 && !out[1].IsNil() {
+			return []reflect.Value{out[1]}
+		}
+
+		engine := args[len(args)-1].Interface().(*gin.Engine)
+		RegisterRoute(engine, route, out[0].Convert(handlerFuncType).Interface().(gin.HandlerFunc))
+
+		return []reflect.Value{reflect.Zero(errorType)}
+	})
+
+	return fn.Interface()
+}
+
+// asHandlerFunc 处理函数是否可以直接作为 gin.HandlerFunc
+func asHandlerFunc(handler any) (gin.HandlerFunc, bool) {
+	switch h := handler.(type) {
+	case gin.HandlerFunc:
+		return h, h != nil
+	case func(*gin.Context):
+		return h, h != nil
+	}
+	return nil, false
+}
+
+// buildRouteTable 生成最终路由表
+// 路由分组注册的路由保留原始配置, MapRoute 注册的路由由 gin 路由映射生成; 按路径与方法排序
+func buildRouteTable(routerMap map[string]*gin.RouterGroup, configured []*web.RouteConfig) []*web.RouteConfig {
+	byKey := make(map[string]*web.RouteConfig, len(configured))
+	for _, route := range configured {
+		byKey[string(route.Method)+":"+route.Path] = route
+	}
+
+	table := make([]*web.RouteConfig, 0, len(routerMap))
+	for key, group := range routerMap {
+		if route, ok := byKey[key]; ok {
+			table = append(table, route)
+			continue
+		}
+
+		var handler any
+		if len(group.Handlers) > 0 {
+			handler = group.Handlers[len(group.Handlers)-1]
+		}
+
+		table = append(table, &web.RouteConfig{
+			Path:           group.Path,
+			Method:         web.RequestMethod(group.Method),
+			Handler:        handler,
+			AllowAnonymous: group.AllowAnonymous,
+			Schemes:        slices.Clone(group.AuthSchemes),
+			Policies:       slices.Clone(group.AuthzPolicies),
+			RateLimiter:    slices.Clone(group.LimitersPolices),
+		})
+	}
+
+	slices.SortFunc(table, func(a, b *web.RouteConfig) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Method), string(b.Method))
+	})
+
+	return table
+}
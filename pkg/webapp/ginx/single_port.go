@@ -0,0 +1,37 @@
+package ginx
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// newMultiplexHandler 单端口模式下的请求分发器
+// HTTP/2 且 content-type 为 application/grpc 的请求交由 gRPC server 处理, 其余请求交由 web 路由处理
+func newMultiplexHandler(handler http.Handler, grpcServer *grpc.Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGrpcRequest(r) {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// isGrpcRequest 是否为 gRPC 请求
+func isGrpcRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// serverProtocols 单端口明文模式下同时启用 HTTP/1 与 h2c; TLS 模式下 HTTP/2 由 ALPN 协商
+func serverProtocols(tls bool) *http.Protocols {
+	protocols := &http.Protocols{}
+	protocols.SetHTTP1(true)
+	if tls {
+		protocols.SetHTTP2(true)
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	return protocols
+}
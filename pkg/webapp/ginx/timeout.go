@@ -0,0 +1,47 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+)
+
+// RouteTimeout 路由超时中间件
+// 将连接的读写截止时间延长至 timeout, 覆盖 server 级别的读写超时; 到期后取消请求上下文,
+// 处理函数尚未写出响应时返回 504
+func RouteTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		deadline := time.Now().Add(timeout)
+
+		// HTTP/1 下覆盖连接级别的截止时间, 不支持时忽略
+		// 请求结束后清除截止时间: net/http 仅在配置了 server 读写超时时才会为下一个请求重置, keep-alive 连接上残留的截止时间会使后续请求超时
+		controller := http.NewResponseController(c.Writer)
+		if controller.SetReadDeadline(deadline) == nil {
+			defer func() { _ = controller.SetReadDeadline(time.Time{}) }()
+		}
+		if controller.SetWriteDeadline(deadline) == nil {
+			defer func() { _ = controller.SetWriteDeadline(time.Time{}) }()
+		}
+
+		ctx, cancel := context.WithDeadline(c.Request.Context(), deadline)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			abortWithProblem(c, problem.New(http.StatusGatewayTimeout, "").WithError(ctx.Err()), func() {
+				c.AbortWithStatus(http.StatusGatewayTimeout)
+			})
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ginx
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/rpc"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// mapTranscodingRoutes 将已注册的 gRPC 服务以 HTTP/JSON 路由暴露
+// 路由使用 gRPC 方法的鉴权方案、授权策略与限流器, 与其他 HTTP 路由经过相同的 web 中间件;
+// 进程内调用同样经过 gRPC 拦截器链, 已由 HTTP 限流中间件计数的请求不再由 gRPC 限流拦截器重复计数
+func mapTranscodingRoutes(engine *gin.Engine, server *grpc.Server, opts *rpc.GrpcOptions, router web.Router, logger *zap.Logger) {
+	if opts.Transcoding == nil {
+		return
+	}
+
+	transcoder := rpc.NewTranscoder(server, opts.Transcoding, opts.MaxRecvMsgSize)
+
+	rules, err := transcoder.Rules()
+	if err != nil {
+		logger.Error("Failed to build gRPC transcoding routes", zap.Error(err))
+		panic(err)
+	}
+
+	for _, rule := range rules {
+		routes := engine.Handle(rule.Method, rule.Path, func(c *gin.Context) {
+			params := make(map[string]string, len(c.Params))
+			for _, param := range c.Params {
+				params[param.Key] = param.Value
+			}
+
+			req := c.Request
+			if c.GetBool(contextRateLimitedKey) {
+				req = req.WithContext(rpc.WithRateLimited(req.Context()))
+			}
+			transcoder.ServeHTTP(c.Writer, req, rule, params)
+		})
+
+		if schemes := router.GrpcSchemes(rule.FullMethod); len(schemes) > 0 {
+			routes = routes.WithAuthSchemes(schemes...)
+		}
+		if policies := router.GrpcPolicies(rule.FullMethod); len(policies) > 0 {
+			routes = routes.WithAuthzPolicies(policies...)
+		}
+		if limiters := router.GrpcRateLimit(rule.FullMethod); len(limiters) > 0 {
+			routes = routes.WithRateLimit(limiters...)
+		}
+		if router.GrpcAllowAnonymous(rule.FullMethod) {
+			routes.WithAllowAnonymous()
+		}
+
+		logger.Info("Mapped gRPC transcoding route",
+			zap.String("method", rule.Method),
+			zap.String("path", rule.Path),
+			zap.String("grpc_method", rule.FullMethod),
+		)
+	}
+}
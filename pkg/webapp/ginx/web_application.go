@@ -5,15 +5,21 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/rpc"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/xiaohangshu-dev/go-workit/pkg/app"
@@ -27,9 +33,16 @@ import (
 type WebApplication struct {
 	*app.Application
 	routeRegistrations      []any
+	routeGroups             *web.GroupRouteConfig
+	routeTable              []*web.RouteConfig
 	grpcServiceConstructors []any
 	handler                 http.Handler
 	server                  *http.Server
+	muxGrpcServer           *grpc.Server // 单端口模式下经 HTTP server 分发的 gRPC server
+	transcodingGrpcServer   *grpc.Server // 独立端口模式下同时经转码路由 ServeHTTP 提供服务的 gRPC server
+	urls                    []*url.URL
+	endpoints               []*endpoint
+	mu                      sync.RWMutex
 	ServerOptions           *web.ServerConfig
 	env                     *web.Environment
 }
@@ -58,7 +71,49 @@ func NewWebApplication(app *app.Application) web.Application {
 	}
 	serverOptions.GrpcPort = strconv.Itoa(grpcPort)
 
-	// 3. environment 默认 prod
+	// 3. single_port 模式下 HTTP 与 gRPC 共用 http_port
+	serverOptions.SinglePort = app.Config().GetBool("server.single_port")
+
+	// 4. tls 证书与私钥需同时配置
+	serverOptions.TLSCertFile = app.Config().GetString("server.tls.cert_file")
+	serverOptions.TLSKeyFile = app.Config().GetString("server.tls.key_file")
+	if (serverOptions.TLSCertFile == "") != (serverOptions.TLSKeyFile == "") {
+		panic("server.tls.cert_file and server.tls.key_file must be set together")
+	}
+
+	// 5. urls 未配置时监听 http_port
+	serverOptions.Urls = app.Config().GetStringSlice("server.urls")
+	if len(serverOptions.Urls) == 0 {
+		scheme := "http"
+		if serverOptions.TLSCertFile != "" {
+			scheme = "https"
+		}
+		serverOptions.Urls = []string{scheme + "://:" + serverOptions.HttpPort}
+	}
+	urls, err := parseUrls(serverOptions.Urls, serverOptions.TLSCertFile != "")
+	if err != nil {
+		panic(err)
+	}
+
+	// 6. http server 超时与限制, 单端口模式下读写超时默认不限制以支持 gRPC 流
+	config := app.Config()
+	defaultTimeout := 15 * time.Second
+	if serverOptions.SinglePort {
+		defaultTimeout = 0
+	}
+	serverOptions.ReadTimeout = durationOrDefault(config, "server.read_timeout", defaultTimeout)
+	serverOptions.ReadHeaderTimeout = durationOrDefault(config, "server.read_header_timeout", 15*time.Second)
+	serverOptions.WriteTimeout = durationOrDefault(config, "server.write_timeout", defaultTimeout)
+	serverOptions.IdleTimeout = durationOrDefault(config, "server.idle_timeout", 60*time.Second)
+	serverOptions.MaxHeaderBytes = config.GetInt("server.max_header_bytes")
+	serverOptions.MaxRequestBodySize = config.GetInt64("server.max_request_body_size")
+	serverOptions.ShutdownTimeout = durationOrDefault(config, "server.shutdown_timeout", 15*time.Second)
+	serverOptions.DrainDelay = durationOrDefault(config, "server.drain_delay", 0)
+	if serverOptions.MaxHeaderBytes < 0 || serverOptions.MaxRequestBodySize < 0 {
+		panic("server.max_header_bytes and server.max_request_body_size must not be negative")
+	}
+
+	// 7. environment 默认 prod
 	environment := strings.ToLower(app.Config().GetString("server.environment"))
 	if environment == "" {
 		environment = "prod"
@@ -70,7 +125,7 @@ func NewWebApplication(app *app.Application) web.Application {
 		panic("invalid environment: " + environment)
 	}
 
-	// 4. 设置 Gin 模式
+	// 8. 设置 Gin 模式
 	switch serverOptions.Environment {
 	case web.Development:
 		gin.SetMode(gin.DebugMode)
@@ -87,14 +142,14 @@ func NewWebApplication(app *app.Application) web.Application {
 
 	e := gin.New()
 
-	// 5. recover 默认启用（除非明确配置为 false）
+	// 9. recover 默认启用（除非明确配置为 false）
 	if serverOptions.UseDefaultRecover = !app.Config().IsSet("server.use_default_recover") ||
 		app.Config().GetBool("server.use_default_recover"); serverOptions.UseDefaultRecover {
 
 		e.Use(newRecoveryWithZap(app.Logger()))
 	}
 
-	// 6. logger 默认启用（除非明确配置为 false）
+	// 10. logger 默认启用（除非明确配置为 false）
 	if serverOptions.UseDefaultLogger = !app.Config().IsSet("server.use_default_logger") ||
 		app.Config().GetBool("server.use_default_logger"); serverOptions.UseDefaultLogger {
 
@@ -103,7 +158,9 @@ func NewWebApplication(app *app.Application) web.Application {
 
 	return &WebApplication{
 		handler:       e,
+		routeGroups:   &web.GroupRouteConfig{},
 		ServerOptions: serverOptions,
+		urls:          urls,
 		env:           env,
 		Application:   app,
 	}
@@ -112,35 +169,131 @@ func NewWebApplication(app *app.Application) web.Application {
 // Run 启动 Web 应用程序
 func (webapp *WebApplication) Run(params ...string) {
 	// HTTP server
+	options := webapp.ServerOptions
 	webapp.server = &http.Server{
-		Addr:         ":" + webapp.ServerOptions.HttpPort,
-		Handler:      webapp.handler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:              ":" + options.HttpPort,
+		Handler:           webapp.httpHandler(),
+		ReadTimeout:       options.ReadTimeout,
+		ReadHeaderTimeout: options.ReadHeaderTimeout,
+		WriteTimeout:      options.WriteTimeout,
+		IdleTimeout:       options.IdleTimeout,
+		MaxHeaderBytes:    options.MaxHeaderBytes,
+	}
+
+	useTLS := options.TLSCertFile != ""
+
+	// 单端口模式需要 HTTP/2 承载 gRPC
+	if options.SinglePort {
+		webapp.server.Protocols = serverProtocols(useTLS)
+	}
+
+	// 独立端口的 gRPC 监听器与 HTTP 监听器一同创建, 平滑重启时一并传递给子进程
+	listenUrls := webapp.urls
+	if len(webapp.grpcServiceConstructors) > 0 && !options.SinglePort {
+		listenUrls = append(slices.Clone(listenUrls), &url.URL{Scheme: grpcScheme, Host: ":" + options.GrpcPort})
 	}
 
 	// Fx 容器配置
 	webapp.AppendContainer(
 		fx.Supply(webapp.handler.(*gin.Engine)),
 
+		// 启动前校验路由与 gRPC 方法规则引用的鉴权方案、授权策略、限流器、输出缓存策略与幂等性配置, 缺失时启动失败
+		fx.Invoke(func(lc fx.Lifecycle, deps routeValidationDeps) {
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					if err := validateRoutes(webapp.Routes(), deps); err != nil {
+						deps.Logger.Error("Invalid route configuration", zap.Error(err))
+						return err
+					}
+					return nil
+				},
+			})
+		}),
+
 		// HTTP 生命周期管理
 		fx.Invoke(func(lc fx.Lifecycle, shutdowner fx.Shutdowner, logger *zap.Logger) {
+			var signals chan os.Signal
+
 			lc.Append(fx.Hook{
 				OnStart: func(ctx context.Context) error {
-					go func() {
-						logger.Info("HTTP server starting",
-							zap.String("port", webapp.ServerOptions.HttpPort))
-						if err := webapp.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-							logger.Error("HTTP server error", zap.Error(err))
-							_ = shutdowner.Shutdown()
+					endpoints, err := listen(listenUrls, useTLS)
+					if err != nil {
+						logger.Error("Failed to listen", zap.Error(err))
+						return err
+					}
+
+					webapp.mu.Lock()
+					webapp.endpoints = endpoints
+					webapp.mu.Unlock()
+
+					for _, e := range endpoints {
+						if e.grpc() {
+							continue
 						}
-					}()
+
+						logger.Info("HTTP server starting",
+							zap.String("address", e.address()),
+							zap.Bool("single_port", options.SinglePort))
+
+						go func(e *endpoint) {
+							var err error
+							if e.tls() {
+								err = webapp.server.ServeTLS(e.listener, options.TLSCertFile, options.TLSKeyFile)
+							} else {
+								err = webapp.server.Serve(e.listener)
+							}
+							if err != nil && err != http.ErrServerClosed {
+								logger.Error("HTTP server error", zap.String("address", e.address()), zap.Error(err))
+								_ = shutdowner.Shutdown()
+							}
+						}(e)
+					}
+
+					// 收到重启信号时将监听器传递给新进程, 当前进程优雅退出
+					if len(restartSignals) > 0 {
+						signals = make(chan os.Signal, 1)
+						signal.Notify(signals, restartSignals...)
+						go func() {
+							for range signals {
+								process, err := startChild(endpoints)
+								if err != nil {
+									logger.Error("Failed to start child process", zap.Error(err))
+									continue
+								}
+								logger.Info("Child process started, shutting down", zap.Int("pid", process.Pid))
+								_ = shutdowner.Shutdown()
+								return
+							}
+						}()
+					}
+
 					return nil
 				},
 				OnStop: func(ctx context.Context) error {
+					if signals != nil {
+						signal.Stop(signals)
+						close(signals)
+					}
 					logger.Info("Shutting down HTTP server")
-					return webapp.server.Shutdown(ctx)
+					err := webapp.server.Shutdown(ctx)
+
+					// 单端口模式下 gRPC 经 ServeHTTP 提供, 其传输层不支持 GracefulStop, HTTP server 关闭后直接停止
+					if webapp.muxGrpcServer != nil {
+						logger.Info("Stopping GRPC server")
+						webapp.muxGrpcServer.Stop()
+					}
+
+					// 启用转码时 gRPC server 须在 HTTP server 之后停止:
+					// 仍有转码请求经 ServeHTTP 处理时 GracefulStop 会 Drain 其传输层并 panic, 此时直接停止
+					if webapp.transcodingGrpcServer != nil {
+						logger.Info("Stopping GRPC server")
+						if err != nil {
+							webapp.transcodingGrpcServer.Stop()
+							return err
+						}
+						return gracefulStop(ctx, webapp.transcodingGrpcServer)
+					}
+					return err
 				},
 			})
 		}),
@@ -149,21 +302,30 @@ func (webapp *WebApplication) Run(params ...string) {
 	// gRPC server 生命周期管理（如果启用）
 	if len(webapp.grpcServiceConstructors) > 0 {
 		webapp.AppendContainer(
-			fx.Provide(func() *grpc.Server {
-				return grpc.NewServer()
-			}),
-			fx.Invoke(func(lc fx.Lifecycle, shutdowner fx.Shutdowner, logger *zap.Logger, grpcSrv *grpc.Server) {
+			fx.Provide(rpc.NewGrpcServer),
+			fx.Invoke(func(lc fx.Lifecycle, shutdowner fx.Shutdowner, logger *zap.Logger, grpcSrv *grpc.Server, grpcOpts *rpc.GrpcOptions) {
+				// 单端口模式下 gRPC 请求由 HTTP server 分发, 随 HTTP server 停止
+				if webapp.ServerOptions.SinglePort {
+					webapp.server.Handler = newMultiplexHandler(webapp.httpHandler(), grpcSrv)
+					webapp.muxGrpcServer = grpcSrv
+					return
+				}
+
+				// 转码路由经 ServeHTTP 调用 gRPC server, 由 HTTP server 停止后再停止
+				if grpcOpts.Transcoding != nil {
+					webapp.transcodingGrpcServer = grpcSrv
+				}
+
 				lc.Append(fx.Hook{
 					OnStart: func(ctx context.Context) error {
+						lis, err := webapp.grpcListener()
+						if err != nil {
+							logger.Error("Failed to listen on GRPC port", zap.Error(err))
+							return err
+						}
+
 						go func() {
-							addr := ":" + webapp.ServerOptions.GrpcPort
-							lis, err := net.Listen("tcp", addr)
-							if err != nil {
-								logger.Error("Failed to listen on GRPC port", zap.Error(err))
-								_ = shutdowner.Shutdown()
-								return
-							}
-							logger.Info("GRPC server starting", zap.String("port", webapp.ServerOptions.GrpcPort))
+							logger.Info("GRPC server starting", zap.String("address", lis.Addr().String()))
 							if err := grpcSrv.Serve(lis); err != nil && err != grpc.ErrServerStopped {
 								logger.Error("GRPC server error", zap.Error(err))
 								_ = shutdowner.Shutdown()
@@ -172,19 +334,11 @@ func (webapp *WebApplication) Run(params ...string) {
 						return nil
 					},
 					OnStop: func(ctx context.Context) error {
-						logger.Info("Stopping GRPC server")
-						stopped := make(chan struct{})
-						go func() {
-							grpcSrv.GracefulStop()
-							close(stopped)
-						}()
-						select {
-						case <-stopped:
+						if webapp.transcodingGrpcServer != nil {
 							return nil
-						case <-ctx.Done():
-							grpcSrv.Stop() // 强制关闭
-							return ctx.Err()
 						}
+						logger.Info("Stopping GRPC server")
+						return gracefulStop(ctx, grpcSrv)
 					},
 				})
 			}),
@@ -198,6 +352,9 @@ func (webapp *WebApplication) Run(params ...string) {
 			invokeFn := makeGrpcInvoke(serviceType, webapp.Logger())
 			webapp.AppendContainer(fx.Invoke(invokeFn))
 		}
+
+		// 服务注册完成后生成 HTTP/JSON 转码路由
+		webapp.AppendContainer(fx.Invoke(mapTranscodingRoutes))
 	}
 
 	// 注册 HTTP 路由
@@ -205,8 +362,37 @@ func (webapp *WebApplication) Run(params ...string) {
 		webapp.AppendContainer(fx.Invoke(r))
 	}
 
+	// 注册路由分组, 路由表在全部路由注册完成后生成
+	configuredRoutes := webapp.routeGroups.Table()
+	for _, route := range configuredRoutes {
+		webapp.AppendContainer(fx.Invoke(makeRouteInvoke(route)))
+	}
+	webapp.AppendContainer(fx.Provide(func() web.RouteTable {
+		return webapp
+	}))
+
+	// 停机排空: 最后注册的钩子最先停止, 先将就绪状态置为 false 再等待负载均衡摘除流量
+	webapp.AppendContainer(fx.Invoke(func(lc fx.Lifecycle, healthCheck web.HealthCheck, logger *zap.Logger) {
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				healthCheck.SetReady(false)
+				if options.DrainDelay <= 0 {
+					return nil
+				}
+
+				logger.Info("Draining before shutdown", zap.Duration("delay", options.DrainDelay))
+				select {
+				case <-time.After(options.DrainDelay):
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		})
+	}))
+
 	// 构建并运行 Fx 应用
-	fxapp := webapp.FxApp(fx.New(webapp.Container()...))
+	fxapp := webapp.FxApp(fx.New(append(webapp.Container(), fx.StopTimeout(options.DrainDelay+options.ShutdownTimeout))...))
 
 	for _, group := range webapp.engine().RouterMap {
 
@@ -214,6 +400,10 @@ func (webapp *WebApplication) Run(params ...string) {
 
 	}
 
+	webapp.mu.Lock()
+	webapp.routeTable = buildRouteTable(webapp.engine().RouterMap, configuredRoutes)
+	webapp.mu.Unlock()
+
 	// 直接使用 Fx 的 Run 来管理生命周期和信号
 	webapp.Logger().Info("Starting application...")
 	fxapp.Run()
@@ -258,6 +448,8 @@ func (a *WebApplication) UseSwagger() web.Application {
 }
 
 // UseCORS 配置跨域
+//
+// Deprecated: 使用 AddCors 配置命名策略并通过 UseCorsPolicies 注册, 路由可通过 WithCors 选择策略
 func (a *WebApplication) UseCORS(fn any) web.Application {
 	exec, ok := fn.(func(*cors.Config))
 	if !ok {
@@ -271,6 +463,25 @@ func (a *WebApplication) UseCORS(fn any) web.Application {
 	return a
 }
 
+// UseCorsPolicies 注册跨域中间件, 需先通过 AddCors 配置; 应在鉴权与限流之前注册, 使预检请求直接响应
+func (a *WebApplication) UseCorsPolicies() web.Application {
+	a.Use(newCors)
+	return a
+}
+
+// UseSecurityHeaders 注册安全响应头中间件, 未传入配置函数时使用默认配置; 应尽早注册以便错误响应同样包含安全响应头
+func (a *WebApplication) UseSecurityHeaders(fn ...func(*web.SecurityHeadersOptions)) web.Application {
+	opts := web.NewSecurityHeadersOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+
+	a.Use(func() *SecurityHeaders {
+		return newSecurityHeaders(opts, a.env)
+	})
+	return a
+}
+
 // UseStaticFiles 配置静态文件
 func (a *WebApplication) UseStaticFiles(urlPath, root string) web.Application {
 	a.engine().Static(urlPath, root)
@@ -278,13 +489,71 @@ func (a *WebApplication) UseStaticFiles(urlPath, root string) web.Application {
 }
 
 // UseHealthCheck 配置健康检查
+// /health 执行全部健康检查; /health/ready 在未就绪(如停机排空)时返回 503
 func (a *WebApplication) UseHealthCheck() web.Application {
-	a.engine().GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
+	a.routeRegistrations = append(a.routeRegistrations, func(engine *gin.Engine, healthCheck web.HealthCheck) {
+		engine.GET("/health", func(c *gin.Context) {
+			report := healthCheck.Check(c.Request.Context())
+			if report.Status != web.Healthy {
+				c.JSON(http.StatusServiceUnavailable, report)
+				return
+			}
+			c.JSON(http.StatusOK, report)
+		})
+
+		engine.GET("/health/ready", func(c *gin.Context) {
+			if !healthCheck.Ready() {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"status": web.Unhealthy})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"status": web.Healthy})
+		})
 	})
 	return a
 }
 
+// Addresses 返回实际监听的地址, 端口配置为 0 时为系统分配的端口, 应用启动前返回空
+func (a *WebApplication) Addresses() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	addresses := make([]string, 0, len(a.endpoints))
+	for _, e := range a.endpoints {
+		addresses = append(addresses, e.address())
+	}
+	return addresses
+}
+
+// grpcListener 返回 gRPC 监听器, systemd 传递的监听器不含 gRPC 端口时自行监听
+func (a *WebApplication) grpcListener() (net.Listener, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, e := range a.endpoints {
+		if e.grpc() {
+			return e.listener, nil
+		}
+	}
+
+	return net.Listen("tcp", ":"+a.ServerOptions.GrpcPort)
+}
+
+// httpHandler 返回 web 请求处理器, 配置了请求体大小限制时进行包装
+func (a *WebApplication) httpHandler() http.Handler {
+	if a.ServerOptions.MaxRequestBodySize > 0 {
+		return http.MaxBytesHandler(a.handler, a.ServerOptions.MaxRequestBodySize)
+	}
+	return a.handler
+}
+
+// durationOrDefault 读取时长配置, 未配置时使用默认值
+func durationOrDefault(config *viper.Viper, key string, value time.Duration) time.Duration {
+	if !config.IsSet(key) {
+		return value
+	}
+	return config.GetDuration(key)
+}
+
 func (a *WebApplication) engine() *gin.Engine {
 	return a.handler.(*gin.Engine)
 }
@@ -292,25 +561,49 @@ func (a *WebApplication) engine() *gin.Engine {
 // MapGrpcServices 注册 gRPC 服务
 func (webapp *WebApplication) MapGrpcServices(constructors ...any) web.Application {
 	for _, constructor := range constructors {
-		webapp.grpcServiceConstructors = append(webapp.grpcServiceConstructors, constructor)
-		webapp.AppendContainer(fx.Provide(constructor))
-
 		// 推断构造函数的返回类型
 		constructorType := reflect.TypeOf(constructor)
 		if constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 {
 			panic("MapGrpcServices: constructor must be a function with at least one return value")
 		}
 
-		serviceType := constructorType.Out(0)
+		// 服务在 Run 时随 gRPC server 一同注册到容器
+		webapp.grpcServiceConstructors = append(webapp.grpcServiceConstructors, constructor)
+	}
 
-		// 对每个具体服务构造出一个 fx.Invoke
-		invokeFn := makeGrpcInvoke(serviceType, webapp.Logger())
-		webapp.AppendContainer(fx.Invoke(invokeFn))
+	return webapp
+}
+
+// UseGrpcInterceptor 注册 gRPC 拦截器, 按注册顺序组成拦截器链
+func (webapp *WebApplication) UseGrpcInterceptor(constructors ...any) web.Application {
+	for _, constructor := range constructors {
+		constructorType := reflect.TypeOf(constructor)
+		if constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 {
+			panic("UseGrpcInterceptor: constructor must be a function that returns Interceptor")
+		}
+
+		webapp.AppendContainer(rpc.ProvideInterceptor(constructor)...)
 	}
 
 	return webapp
 }
 
+// gracefulStop 优雅停止 gRPC server, 超时后强制关闭
+func gracefulStop(ctx context.Context, grpcSrv *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcSrv.Stop() // 强制关闭
+		return ctx.Err()
+	}
+}
+
 func makeGrpcInvoke(serviceType reflect.Type, logger *zap.Logger) any {
 	// 构造函数类型：func(*grpc.Server, <YourServiceType>)
 	fnType := reflect.FuncOf(
@@ -403,6 +696,15 @@ func (a *WebApplication) UseRecovery() web.Application {
 	return a
 }
 
+// UseExceptionHandler 注册异常处理中间件, 将错误与 panic 渲染为 Problem Details
+// 应在鉴权、限流等中间件之前注册, 以便其失败响应同样以 Problem Details 输出
+func (a *WebApplication) UseExceptionHandler() web.Application {
+	a.Use(func(mapper web.ProblemMapper, logger *zap.Logger) *ExceptionHandler {
+		return newExceptionHandler(mapper, a.env, logger)
+	})
+	return a
+}
+
 // UseLogger 注册日志中间件, 用于记录请求日志
 func (a *WebApplication) UseLogger() web.Application {
 	a.engine().Use(newZapLogger(a.Logger()))
@@ -421,8 +723,66 @@ func (a *WebApplication) UseRateLimiter() web.Application {
 	return a
 }
 
+// UseGrpcAuthentication 配置 gRPC 鉴权拦截器
+func (a *WebApplication) UseGrpcAuthentication() web.Application {
+	a.UseGrpcInterceptor(rpc.NewAuthenticate)
+	return a
+}
+
+// UseGrpcAuthorization 配置 gRPC 授权拦截器
+func (a *WebApplication) UseGrpcAuthorization() web.Application {
+	a.UseGrpcInterceptor(rpc.NewAuthorize)
+	return a
+}
+
+// UseGrpcRateLimiter 配置 gRPC 限流功能
+func (a *WebApplication) UseGrpcRateLimiter() web.Application {
+	a.UseGrpcInterceptor(rpc.NewRateLimiter)
+	return a
+}
+
 // UseReqDecomp 配置请求解压
 func (a *WebApplication) UseRequestDecompression() web.Application {
 	a.Use(newDecompression)
 	return a
 }
+
+// UseResponseCompression 配置响应压缩
+func (a *WebApplication) UseResponseCompression() web.Application {
+	a.Use(newRespCompression)
+	return a
+}
+
+// UseApiVersioning 配置 API 版本, 需先通过 AddApiVersioning 配置; 应在鉴权中间件之前注册
+func (a *WebApplication) UseApiVersioning() web.Application {
+	a.Use(newApiVersioning)
+	return a
+}
+
+// UseOutputCache 配置输出缓存, 需先通过 AddOutputCache 配置
+// 应在鉴权之后注册以便按用户区分缓存; 同时启用响应压缩时应在其之后注册, 使缓存保存未压缩的响应
+func (a *WebApplication) UseOutputCache() web.Application {
+	a.Use(newOutputCache)
+	return a
+}
+
+// UseIdempotency 按幂等键保存并重放响应, 需先通过 AddIdempotency 配置
+// 应在鉴权之后注册以便按用户区分幂等键; 同时启用响应压缩时应在其之后注册, 使记录保存未压缩的响应
+func (a *WebApplication) UseIdempotency() web.Application {
+	a.Use(newIdempotency)
+	return a
+}
+
+// UseAntiforgery 校验非安全方法请求的防伪令牌, 需先通过 AddAntiforgery 配置; 应在鉴权之后注册, 以便同步令牌与用户绑定
+func (a *WebApplication) UseAntiforgery() web.Application {
+	a.Use(newAntiforgery)
+	return a
+}
+
+// UseHttpCaching 按路由配置写出 Cache-Control、生成 ETag 并处理条件请求
+// 同时启用响应压缩时应在其之后注册, 使 ETag 按未压缩的响应体生成; 在输出缓存之前注册时缓存命中的响应同样支持 304
+// 响应被压缩时强 ETag 由压缩中间件改为弱 ETag
+func (a *WebApplication) UseHttpCaching() web.Application {
+	a.Use(newHttpCaching)
+	return a
+}
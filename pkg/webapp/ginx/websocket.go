@@ -0,0 +1,64 @@
+package ginx
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+var (
+	websocketHandlerType = reflect.TypeOf(web.WebSocketHandler(nil))
+	websocketServerType  = reflect.TypeOf((*web.WebSocketServer)(nil)).Elem()
+)
+
+// MapWebSocket 注册 WebSocket 路由, 握手请求经过鉴权、授权与限流中间件后升级连接
+// handler 可以是 web.WebSocketHandler, 也可以是返回 web.WebSocketHandler 的构造函数, 构造函数的参数由容器注入
+func (a *WebApplication) MapWebSocket(path string, handler any) *web.RouteConfig {
+	return a.routeGroups.MapGet(path, makeWebSocketHandler(path, handler)).ExcludeFromDescription()
+}
+
+// makeWebSocketHandler 生成路由处理函数的构造函数, 在 WebSocket 处理函数的参数之外注入 web.WebSocketServer
+func makeWebSocketHandler(path string, handler any) any {
+	switch h := handler.(type) {
+	case web.WebSocketHandler:
+		return func(server web.WebSocketServer) gin.HandlerFunc {
+			return server.Handler(h)
+		}
+	case func(web.WebSocketConn) error:
+		return func(server web.WebSocketServer) gin.HandlerFunc {
+			return server.Handler(h)
+		}
+	}
+
+	constructorType := reflect.TypeOf(handler)
+	if constructorType == nil || constructorType.Kind() != reflect.Func ||
+		constructorType.NumOut() == 0 || constructorType.NumOut() > 2 ||
+		!constructorType.Out(0).ConvertibleTo(websocketHandlerType) ||
+		(constructorType.NumOut() == 2 && constructorType.Out(1) != errorType) {
+		panic(fmt.Sprintf("websocket %s: handler must be web.WebSocketHandler or a constructor returning web.WebSocketHandler", path))
+	}
+
+	in := make([]reflect.Type, 0, constructorType.NumIn()+1)
+	for i := 0; i < constructorType.NumIn(); i++ {
+		in = append(in, constructorType.In(i))
+	}
+	in = append(in, websocketServerType)
+
+	fnType := reflect.FuncOf(in, []reflect.Type{handlerFuncType, errorType}, false)
+	constructor := reflect.ValueOf(handler)
+
+	fn := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		out := constructor.Call(args[:len(args)-1])
+		if len(out) == 2 && !out[1].IsNil() {
+			return []reflect.Value{reflect.Zero(handlerFuncType), out[1]}
+		}
+
+		server := args[len(args)-1].Interface().(web.WebSocketServer)
+		h := out[0].Convert(websocketHandlerType).Interface().(web.WebSocketHandler)
+		return []reflect.Value{reflect.ValueOf(server.Handler(h)), reflect.Zero(errorType)}
+	})
+
+	return fn.Interface()
+}
@@ -0,0 +1,74 @@
+package grpcctx
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/xiaohangshu-dev/go-workit/pkg/components/grpcx"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// Options gRPC 客户端选项
+type Options struct {
+	container []fx.Option         // 持有容器引用
+	clientMap map[string]struct{} // 客户端名称集合
+}
+
+// NewOptions 创建 gRPC 客户端选项
+func NewOptions() *Options {
+	return &Options{
+		container: make([]fx.Option, 0),
+		clientMap: make(map[string]struct{}),
+	}
+}
+
+// AddClient 注册类型化的 gRPC 客户端, newClient 为 protoc 生成的构造函数, 如 pb.NewHelloServiceClient
+// 客户端选项先从配置 grpc_clients.<name> 加载, 再由 fn 覆盖
+// 若 name 为空，则注册名为 default 的客户端，可直接注入
+// 若 name 非空，则注册显式命名的客户端，使用 name 标签注入
+func AddClient[T any](o *Options, name string, newClient func(grpc.ClientConnInterface) T, fn ...func(*grpcx.Options)) *Options {
+	if name == "" {
+		name = "default"
+	}
+
+	if _, ok := o.clientMap[name]; ok {
+		panic("grpc client name already exists")
+	}
+
+	constructor := func(lc fx.Lifecycle, config *viper.Viper, logger *zap.Logger) T {
+		opts := grpcx.NewOptions()
+
+		if err := config.UnmarshalKey("grpc_clients."+name, opts); err != nil {
+			panic(fmt.Errorf("load grpc client %s config: %w", name, err))
+		}
+
+		for _, f := range fn {
+			f(opts)
+		}
+
+		return newClient(grpcx.NewClientConn(lc, name, opts, logger))
+	}
+
+	if name == "default" {
+		o.container = append(o.container, fx.Provide(constructor))
+	} else {
+		o.container = append(o.container,
+			fx.Provide(
+				fx.Annotate(
+					constructor,
+					fx.ResultTags(`name:"`+name+`"`),
+				),
+			),
+		)
+	}
+
+	o.clientMap[name] = struct{}{}
+
+	return o
+}
+
+func (o *Options) Container() []fx.Option {
+	return o.container
+}
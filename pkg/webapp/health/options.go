@@ -0,0 +1,35 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// Options 健康检查选项
+type Options struct {
+	Timeout time.Duration                              // 单项检查超时时间
+	checks  map[string]func(ctx context.Context) error // 健康检查 (name -> check)
+}
+
+// NewOptions 创建健康检查选项
+func NewOptions() *Options {
+	return &Options{
+		Timeout: 5 * time.Second,
+		checks:  make(map[string]func(ctx context.Context) error),
+	}
+}
+
+// AddCheck 添加健康检查, check 返回 nil 表示健康
+func (o *Options) AddCheck(name string, check func(ctx context.Context) error) *Options {
+	if _, exists := o.checks[name]; exists {
+		panic("health check with name " + name + " already exists")
+	}
+
+	o.checks[name] = check
+	return o
+}
+
+// Checks 返回所有健康检查
+func (o *Options) Checks() map[string]func(ctx context.Context) error {
+	return o.checks
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// Registry 健康检查注册表
+type Registry struct {
+	checks  map[string]func(ctx context.Context) error
+	timeout time.Duration
+	ready   atomic.Bool
+}
+
+// NewRegistry 创建健康检查注册表, 初始为就绪状态
+func NewRegistry(opts *Options) *Registry {
+	r := &Registry{
+		checks:  opts.Checks(),
+		timeout: opts.Timeout,
+	}
+	r.ready.Store(true)
+	return r
+}
+
+// Check 并发执行健康检查, 任一检查失败时整体不健康
+func (r *Registry) Check(ctx context.Context, names ...string) web.HealthReport {
+	if len(names) == 0 {
+		for name := range r.checks {
+			names = append(names, name)
+		}
+	}
+
+	report := web.HealthReport{
+		Status:  web.Healthy,
+		Entries: make(map[string]web.HealthEntry, len(names)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for _, name := range names {
+		check, ok := r.checks[name]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+
+			entry := r.run(ctx, check)
+
+			mu.Lock()
+			report.Entries[name] = entry
+			if entry.Status != web.Healthy {
+				report.Status = web.Unhealthy
+			}
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// run 执行单项检查
+func (r *Registry) run(ctx context.Context, check func(ctx context.Context) error) (entry web.HealthEntry) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	defer func() {
+		if err := recover(); err != nil {
+			entry = web.HealthEntry{Status: web.Unhealthy, Error: "health check panic", Duration: time.Since(start)}
+		}
+	}()
+
+	if err := check(ctx); err != nil {
+		return web.HealthEntry{Status: web.Unhealthy, Error: err.Error(), Duration: time.Since(start)}
+	}
+
+	return web.HealthEntry{Status: web.Healthy, Duration: time.Since(start)}
+}
+
+// Has 是否注册了指定名称的检查
+func (r *Registry) Has(name string) bool {
+	_, ok := r.checks[name]
+	return ok
+}
+
+// Ready 是否就绪
+func (r *Registry) Ready() bool {
+	return r.ready.Load()
+}
+
+// SetReady 设置就绪状态
+func (r *Registry) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
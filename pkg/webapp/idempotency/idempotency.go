@@ -0,0 +1,48 @@
+package idempotency
+
+import "time"
+
+// Idempotency 幂等性配置
+type Idempotency struct {
+	opts *Options
+}
+
+// NewIdempotency 创建幂等性配置
+func NewIdempotency(opts *Options) *Idempotency {
+	return &Idempotency{opts: opts}
+}
+
+// HeaderName 返回幂等键请求头
+func (i *Idempotency) HeaderName() string {
+	return i.opts.HeaderName
+}
+
+// RequireKey 缺少幂等键时是否拒绝请求
+func (i *Idempotency) RequireKey() bool {
+	return i.opts.RequireKey
+}
+
+// MaxKeyLength 返回幂等键最大长度
+func (i *Idempotency) MaxKeyLength() int {
+	return i.opts.MaxKeyLength
+}
+
+// Expire 返回处理结果的保存时间
+func (i *Idempotency) Expire() time.Duration {
+	return i.opts.Expire
+}
+
+// InFlightTimeout 返回处理中记录的保存时间
+func (i *Idempotency) InFlightTimeout() time.Duration {
+	return i.opts.InFlightTimeout
+}
+
+// MaxBodySize 返回可保存的最大响应体字节数
+func (i *Idempotency) MaxBodySize() int64 {
+	return i.opts.MaxBodySize
+}
+
+// MaxRequestBodySize 返回计算指纹时可读取的最大请求体字节数
+func (i *Idempotency) MaxRequestBodySize() int64 {
+	return i.opts.MaxRequestBodySize
+}
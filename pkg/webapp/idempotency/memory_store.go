@@ -0,0 +1,39 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/tools/cache"
+)
+
+// MemoryStore 内存幂等记录存储, 仅在单个实例内有效
+type MemoryStore struct {
+	cache *cache.MemoryCache
+}
+
+// NewMemoryStore 创建内存幂等记录存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cache: cache.NewMemoryCache()}
+}
+
+// Reserve 键不存在时写入记录
+func (s *MemoryStore) Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	existing, ok := s.cache.Add(key, value, ttl)
+	if !ok {
+		return existing.([]byte), false, nil
+	}
+	return nil, true, nil
+}
+
+// Complete 保存处理结果
+func (s *MemoryStore) Complete(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.cache.Set(key, value, ttl)
+	return nil
+}
+
+// Release 删除记录
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.cache.Delete(key)
+	return nil
+}
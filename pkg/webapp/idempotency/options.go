@@ -0,0 +1,39 @@
+package idempotency
+
+import "time"
+
+// Options 幂等性配置
+type Options struct {
+	HeaderName         string        // 幂等键请求头
+	RequireKey         bool          // 缺少幂等键时返回 400, 默认不启用幂等处理直接执行
+	MaxKeyLength       int           // 幂等键最大长度
+	Expire             time.Duration // 处理结果的保存时间, 期间使用相同键的请求重放保存的响应
+	InFlightTimeout    time.Duration // 处理中记录的保存时间, 应大于路由的最长处理时间
+	MaxBodySize        int64         // 可保存的最大响应体字节数, 超过时不保存结果
+	MaxRequestBodySize int64         // 计算指纹时可读取的最大请求体字节数, 超过时返回 413, 0 表示不限制
+	store              any
+}
+
+// NewOptions 返回默认配置, 处理结果保存 24 小时, 默认使用内存存储
+func NewOptions() *Options {
+	return &Options{
+		HeaderName:         "Idempotency-Key",
+		MaxKeyLength:       255,
+		Expire:             24 * time.Hour,
+		InFlightTimeout:    time.Minute,
+		MaxBodySize:        1 << 20,
+		MaxRequestBodySize: 1 << 20,
+	}
+}
+
+// UseStore 使用自定义幂等记录存储, constructor 为返回 web.IdempotencyStore 实现的构造函数, 参数由容器注入
+// 如 UseStore(idempotency.NewRedisStore) 使用默认 Redis 客户端, UseStore(idempotency.NewSqlStore) 使用默认数据库
+func (o *Options) UseStore(constructor any) *Options {
+	o.store = constructor
+	return o
+}
+
+// Store 返回自定义幂等记录存储的构造函数, 未配置时返回 nil
+func (o *Options) Store() any {
+	return o.store
+}
@@ -0,0 +1,56 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisKeyPrefix Redis 幂等记录键前缀
+const redisKeyPrefix = "idempotency:"
+
+// RedisStore Redis 幂等记录存储, 多个实例共享记录
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 创建 Redis 幂等记录存储
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Reserve 以 SETNX 写入记录, 键已存在时返回已保存的记录
+func (s *RedisStore) Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	// 读取已保存记录时键可能恰好过期, 重试一次
+	for range 2 {
+		ok, err := s.client.SetNX(ctx, redisKeyPrefix+key, value, ttl).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		existing, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+	return nil, false, errors.New("idempotency key expired while being reserved")
+}
+
+// Complete 保存处理结果
+func (s *RedisStore) Complete(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, redisKeyPrefix+key, value, ttl).Err()
+}
+
+// Release 删除记录
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, redisKeyPrefix+key).Err()
+}
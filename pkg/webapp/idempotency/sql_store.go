@@ -0,0 +1,160 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sqlTable 幂等记录表
+const sqlTable = "idempotency_records"
+
+// sqlDialect 数据库方言, 按驱动类型识别
+type sqlDialect int
+
+const (
+	dialectMySQL sqlDialect = iota
+	dialectPostgres
+	dialectSQLite
+	dialectSQLServer
+)
+
+// sqlSchemas 各方言的建表语句, 幂等键为固定长度的摘要
+var sqlSchemas = map[sqlDialect]string{
+	dialectMySQL: `CREATE TABLE IF NOT EXISTS ` + sqlTable + ` (
+		idempotency_key VARCHAR(64) NOT NULL PRIMARY KEY,
+		payload LONGBLOB NOT NULL,
+		expires_at BIGINT NOT NULL)`,
+	dialectPostgres: `CREATE TABLE IF NOT EXISTS ` + sqlTable + ` (
+		idempotency_key VARCHAR(64) NOT NULL PRIMARY KEY,
+		payload BYTEA NOT NULL,
+		expires_at BIGINT NOT NULL)`,
+	dialectSQLite: `CREATE TABLE IF NOT EXISTS ` + sqlTable + ` (
+		idempotency_key VARCHAR(64) NOT NULL PRIMARY KEY,
+		payload BLOB NOT NULL,
+		expires_at BIGINT NOT NULL)`,
+	dialectSQLServer: `IF OBJECT_ID(N'` + sqlTable + `', N'U') IS NULL CREATE TABLE ` + sqlTable + ` (
+		idempotency_key VARCHAR(64) NOT NULL PRIMARY KEY,
+		payload VARBINARY(MAX) NOT NULL,
+		expires_at BIGINT NOT NULL)`,
+}
+
+// SqlStore 关系型数据库幂等记录存储, 支持 MySQL、PostgreSQL、SQLite 与 SQL Server, 首次使用时自动建表
+// 以主键冲突保证同一键只有一个请求执行
+type SqlStore struct {
+	db      *sql.DB
+	dialect sqlDialect
+	mu      sync.Mutex
+	ready   bool
+}
+
+// NewSqlStore 创建关系型数据库幂等记录存储
+func NewSqlStore(db *sql.DB) *SqlStore {
+	return &SqlStore{db: db, dialect: detectDialect(db)}
+}
+
+// detectDialect 按驱动类型识别数据库方言, 无法识别时按 MySQL 处理
+func detectDialect(db *sql.DB) sqlDialect {
+	name := strings.ToLower(reflect.TypeOf(db.Driver()).String())
+	switch {
+	case strings.Contains(name, "pq.") || strings.Contains(name, "pgx") || strings.Contains(name, "postgres"):
+		return dialectPostgres
+	case strings.Contains(name, "sqlite"):
+		return dialectSQLite
+	case strings.Contains(name, "mssql") || strings.Contains(name, "sqlserver"):
+		return dialectSQLServer
+	default:
+		return dialectMySQL
+	}
+}
+
+// Reserve 插入记录, 主键冲突时返回未过期的已保存记录
+func (s *SqlStore) Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	if err := s.ensureSchema(ctx); err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	if _, err := s.db.ExecContext(ctx,
+		s.query("DELETE FROM %s WHERE idempotency_key = %s AND expires_at <= %s", 2),
+		key, now.UnixMilli()); err != nil {
+		return nil, false, err
+	}
+
+	_, insertErr := s.db.ExecContext(ctx,
+		s.query("INSERT INTO %s (idempotency_key, payload, expires_at) VALUES (%s, %s, %s)", 3),
+		key, value, now.Add(ttl).UnixMilli())
+	if insertErr == nil {
+		return nil, true, nil
+	}
+
+	var existing []byte
+	err := s.db.QueryRowContext(ctx,
+		s.query("SELECT payload FROM %s WHERE idempotency_key = %s AND expires_at > %s", 2),
+		key, now.UnixMilli()).Scan(&existing)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, insertErr
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+// Complete 保存处理结果
+func (s *SqlStore) Complete(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		s.query("UPDATE %s SET payload = %s, expires_at = %s WHERE idempotency_key = %s", 3),
+		value, time.Now().Add(ttl).UnixMilli(), key)
+	return err
+}
+
+// Release 删除记录
+func (s *SqlStore) Release(ctx context.Context, key string) error {
+	if err := s.ensureSchema(ctx); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx, s.query("DELETE FROM %s WHERE idempotency_key = %s", 1), key)
+	return err
+}
+
+// ensureSchema 首次使用时建表, 失败时下次使用重试
+func (s *SqlStore) ensureSchema(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ready {
+		return nil
+	}
+	if _, err := s.db.ExecContext(ctx, sqlSchemas[s.dialect]); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", sqlTable, err)
+	}
+	s.ready = true
+	return nil
+}
+
+// query 填充表名与方言对应的参数占位符
+func (s *SqlStore) query(format string, params int) string {
+	args := []any{sqlTable}
+	for i := 1; i <= params; i++ {
+		switch s.dialect {
+		case dialectPostgres:
+			args = append(args, fmt.Sprintf("$%d", i))
+		case dialectSQLServer:
+			args = append(args, fmt.Sprintf("@p%d", i))
+		default:
+			args = append(args, "?")
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
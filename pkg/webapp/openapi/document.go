@@ -0,0 +1,90 @@
+package openapi
+
+// Version 生成的 OpenAPI 版本
+const Version = "3.1.0"
+
+// Document OpenAPI 文档
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Servers    []Server                         `json:"servers,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Tags       []Tag                            `json:"tags,omitempty"`
+}
+
+// Server 服务地址
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag 标签
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Components 可复用组件
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 鉴权方案
+type SecurityScheme struct {
+	Type         string `json:"type"`                   // http、apiKey、oauth2、openIdConnect
+	Description  string `json:"description,omitempty"`  // 描述
+	Scheme       string `json:"scheme,omitempty"`       // http 鉴权方案, 如 bearer、basic
+	BearerFormat string `json:"bearerFormat,omitempty"` // bearer 令牌格式, 如 JWT
+	Name         string `json:"name,omitempty"`         // apiKey 参数名
+	In           string `json:"in,omitempty"`           // apiKey 参数位置: header、query、cookie
+}
+
+// SecurityRequirement 鉴权要求 (scheme -> scopes)
+type SecurityRequirement map[string][]string
+
+// Operation 接口操作
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter 请求参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path、query、header
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Deprecated  bool    `json:"deprecated,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Header 响应头
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType 媒体类型
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
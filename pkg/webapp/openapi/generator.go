@@ -0,0 +1,511 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth/scheme/jwt"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/versioning"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// problemSchemaName Problem Details 组件名称
+const problemSchemaName = "ProblemDetails"
+
+// Generator OpenAPI 文档生成器
+// 路由表在应用启动后才完整, 文档在首次请求时生成并缓存
+type Generator struct {
+	opts   *Options
+	routes web.RouteTable
+	router web.Router
+	mu     sync.Mutex
+	cache  map[string][]byte
+}
+
+// NewGenerator 创建文档生成器
+func NewGenerator(opts *Options, routes web.RouteTable, router web.Router) *Generator {
+	return &Generator{
+		opts:   opts,
+		routes: routes,
+		router: router,
+		cache:  make(map[string][]byte),
+	}
+}
+
+// Documents 返回文档名称, 未添加文档时为默认文档
+func (g *Generator) Documents() []string {
+	documents := g.documents()
+	names := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		names = append(names, doc.name)
+	}
+	return names
+}
+
+// Document 返回文档 JSON
+func (g *Generator) Document(name string) ([]byte, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if data, ok := g.cache[name]; ok {
+		return data, true
+	}
+
+	doc, ok := g.Generate(name)
+	if !ok {
+		return nil, false
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false
+	}
+
+	// 应用启动前路由表为空, 不缓存
+	if len(g.routes.Routes()) > 0 {
+		g.cache[name] = data
+	}
+	return data, true
+}
+
+// Path 返回文档地址前缀
+func (g *Generator) Path() string {
+	return g.opts.Path
+}
+
+// UIPath 返回文档页面地址
+func (g *Generator) UIPath() string {
+	if g.opts.UI == NoUI {
+		return ""
+	}
+	return g.opts.UIPath
+}
+
+// UIPage 返回文档页面
+func (g *Generator) UIPage() []byte {
+	return renderUI(g.opts)
+}
+
+// UIScript 返回文档页面启动脚本
+func (g *Generator) UIScript() []byte {
+	return renderUIScript(g.opts, g.Documents())
+}
+
+// UIFiles 返回文档页面内置静态资源, 使用外部资源时返回 nil
+func (g *Generator) UIFiles() http.FileSystem {
+	return uiFiles(g.opts)
+}
+
+// Generate 生成指定名称的文档
+func (g *Generator) Generate(name string) (*Document, bool) {
+	var (
+		current document
+		found   bool
+	)
+	for _, doc := range g.documents() {
+		if doc.name == name {
+			current, found = doc, true
+			break
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	b := &documentBuilder{
+		generator: g,
+		current:   current,
+		registry:  newSchemaRegistry(map[reflect.Type]string{problemType: problemSchemaName}),
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    current.info,
+			Paths:   make(map[string]map[string]*Operation),
+		},
+		securitySchemes: make(map[string]*SecurityScheme),
+	}
+
+	for _, server := range g.opts.Servers {
+		b.doc.Servers = append(b.doc.Servers, Server{URL: server})
+	}
+
+	for _, route := range g.routes.Routes() {
+		if !g.describes(route, current) {
+			continue
+		}
+		b.addRoute(route)
+	}
+
+	b.finish()
+	return b.doc, true
+}
+
+// documents 返回已添加的文档, 未添加时为默认文档
+func (g *Generator) documents() []document {
+	if len(g.opts.documents) > 0 {
+		return g.opts.documents
+	}
+	return []document{{name: DefaultDocument, info: Info{Title: "API", Version: "1.0.0"}}}
+}
+
+// describes 路由是否出现在指定文档中
+func (g *Generator) describes(route *web.RouteConfig, doc document) bool {
+	if route.ExcludedFromDescription {
+		return false
+	}
+	if route.GroupName != "" && route.GroupName != doc.name {
+		return false
+	}
+	if doc.apiVersion != "" && route.Versioned() && !supportsVersion(route, doc.apiVersion) {
+		return false
+	}
+
+	// 文档与页面路由本身不出现在文档中
+	switch route.Path {
+	case g.opts.Path + ".json", g.opts.Path + "/:document", g.opts.UIPath,
+		g.opts.UIPath + web.OpenApiUIScriptPath, g.opts.UIPath + web.OpenApiUIAssetsPath + "/*filepath":
+		return false
+	}
+
+	return true
+}
+
+// securityScheme 返回鉴权方案的文档描述
+func (g *Generator) securityScheme(scheme string) (*SecurityScheme, bool) {
+	if securityScheme, ok := g.opts.securitySchemes[scheme]; ok {
+		return securityScheme, true
+	}
+
+	handler, ok := g.router.Authenticate(scheme)
+	if !ok {
+		return nil, false
+	}
+
+	switch handler.Type() {
+	case jwt.SchemeJwtBearer:
+		return &SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}, true
+	}
+	return nil, false
+}
+
+// documentBuilder 单个文档的生成状态
+type documentBuilder struct {
+	generator       *Generator
+	current         document
+	registry        *schemaRegistry
+	doc             *Document
+	securitySchemes map[string]*SecurityScheme
+	tags            []string
+}
+
+// addRoute 将路由添加为文档操作
+func (b *documentBuilder) addRoute(route *web.RouteConfig) {
+	path, pathParams := openapiPath(route.Path)
+
+	operation := &Operation{
+		OperationID: route.Name,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Tags:        route.Tags,
+		Responses:   make(map[string]*Response),
+		Deprecated:  b.current.apiVersion != "" && containsVersion(route.DeprecatedApiVersions, b.current.apiVersion),
+	}
+	if len(operation.Tags) == 0 {
+		if tag := defaultTag(route.Path); tag != "" {
+			operation.Tags = []string{tag}
+		}
+	}
+	for _, tag := range operation.Tags {
+		if !slices.Contains(b.tags, tag) {
+			b.tags = append(b.tags, tag)
+		}
+	}
+
+	b.addParameters(operation, route, pathParams)
+	b.addRequestBody(operation, route)
+	b.addResponses(operation, route)
+	b.addSecurity(operation, route)
+
+	if b.doc.Paths[path] == nil {
+		b.doc.Paths[path] = make(map[string]*Operation)
+	}
+	b.doc.Paths[path][strings.ToLower(string(route.Method))] = operation
+}
+
+// addParameters 添加路径、查询与请求头参数
+// 路径参数取自路由模板, 请求模型中 path、query、header 标签的字段补充类型与约束
+func (b *documentBuilder) addParameters(operation *Operation, route *web.RouteConfig, pathParams []string) {
+	declared := make(map[string]*Parameter)
+
+	for _, field := range structFields(requestStruct(route.Request)) {
+		for _, in := range []string{"path", "query", "header"} {
+			name := tagName(field, in)
+			if name == "" {
+				continue
+			}
+
+			schema := b.registry.schema(field.Type)
+			applyFieldTags(schema, field)
+			param := &Parameter{
+				Name:        name,
+				In:          in,
+				Description: schema.Description,
+				Required:    in == "path" || isRequired(field),
+				Schema:      schema,
+			}
+			schema.Description = ""
+
+			if in == "path" {
+				declared[name] = param
+				continue
+			}
+			operation.Parameters = append(operation.Parameters, param)
+		}
+	}
+
+	params := make([]*Parameter, 0, len(pathParams)+len(operation.Parameters))
+	for _, name := range pathParams {
+		if param, ok := declared[name]; ok {
+			params = append(params, param)
+			continue
+		}
+		params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	operation.Parameters = append(params, operation.Parameters...)
+}
+
+// addRequestBody 添加请求体
+// 结构体中未声明 path、query、header 标签的字段作为 JSON 请求体, 声明了 form 标签的字段作为表单请求体
+func (b *documentBuilder) addRequestBody(operation *Operation, route *web.RouteConfig) {
+	if route.Request == nil {
+		return
+	}
+	switch route.Method {
+	case web.GET, web.HEAD, web.DELETE, web.OPTIONS:
+		return
+	}
+
+	t := route.Request
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	content := make(map[string]*MediaType)
+	if t.Kind() != reflect.Struct {
+		content["application/json"] = &MediaType{Schema: b.registry.schema(t)}
+	} else {
+		isBody := func(field reflect.StructField) bool {
+			return tagName(field, "path") == "" && tagName(field, "query") == "" && tagName(field, "header") == ""
+		}
+		isForm := func(field reflect.StructField) bool {
+			return tagName(field, "form") != ""
+		}
+
+		var bodyFields, formFields int
+		for _, field := range structFields(t) {
+			if isBody(field) {
+				bodyFields++
+			}
+			if isForm(field) {
+				formFields++
+			}
+		}
+
+		switch {
+		case bodyFields == len(structFields(t)) && t.Name() != "":
+			// 全部字段均来自请求体时直接引用组件
+			content["application/json"] = &MediaType{Schema: b.registry.schema(t)}
+		case bodyFields > 0:
+			content["application/json"] = &MediaType{Schema: b.registry.object(t, isBody)}
+		}
+
+		if formFields > 0 {
+			form := b.formObject(t)
+			content["application/x-www-form-urlencoded"] = &MediaType{Schema: form}
+			content["multipart/form-data"] = &MediaType{Schema: form}
+		}
+	}
+
+	if len(content) == 0 {
+		return
+	}
+	operation.RequestBody = &RequestBody{Required: true, Content: content}
+}
+
+// formObject 生成表单请求体, 属性名取自 form 标签
+func (b *documentBuilder) formObject(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range structFields(t) {
+		name := tagName(field, "form")
+		if name == "" {
+			continue
+		}
+
+		property := b.registry.schema(field.Type)
+		applyFieldTags(property, field)
+		schema.Properties[name] = property
+		if isRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// addResponses 添加响应
+// 声明了请求模型时补充 400, 需要鉴权时补充 401, 配置了授权策略时补充 403, 存在限流时补充 429
+func (b *documentBuilder) addResponses(operation *Operation, route *web.RouteConfig) {
+	for _, response := range route.Responses {
+		resp := &Response{Description: statusDescription(response.Status)}
+		if response.Type != nil {
+			contentType := response.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			resp.Content = map[string]*MediaType{contentType: {Schema: b.registry.schema(response.Type)}}
+		}
+		operation.Responses[strconv.Itoa(response.Status)] = resp
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["200"] = &Response{Description: statusDescription(http.StatusOK)}
+	}
+
+	if route.Request != nil {
+		b.addProblem(operation, http.StatusBadRequest)
+	}
+	if !route.AllowAnonymous {
+		b.addProblem(operation, http.StatusUnauthorized)
+		if len(route.Policies) > 0 {
+			b.addProblem(operation, http.StatusForbidden)
+		}
+	}
+	if !slices.Contains(route.RateLimiter, web.RateLimitDisabled) &&
+		(len(route.RateLimiter) > 0 || b.generator.router.GlobalRatelimit() != "") {
+		if b.addProblem(operation, http.StatusTooManyRequests) {
+			operation.Responses["429"].Headers = map[string]*Header{
+				"Retry-After": {Description: "seconds to wait before retrying", Schema: &Schema{Type: "integer"}},
+			}
+		}
+	}
+}
+
+// addProblem 添加 Problem Details 响应, 已声明该状态码时不覆盖
+func (b *documentBuilder) addProblem(operation *Operation, status int) bool {
+	key := strconv.Itoa(status)
+	if _, exists := operation.Responses[key]; exists {
+		return false
+	}
+
+	operation.Responses[key] = &Response{
+		Description: statusDescription(status),
+		Content: map[string]*MediaType{
+			problem.ContentType: {Schema: b.registry.schema(problemType)},
+		},
+	}
+	return true
+}
+
+// addSecurity 添加鉴权要求, 多个鉴权方案任一通过即可
+func (b *documentBuilder) addSecurity(operation *Operation, route *web.RouteConfig) {
+	if route.AllowAnonymous {
+		return
+	}
+
+	schemes := route.Schemes
+	if len(schemes) == 0 {
+		if scheme := b.generator.router.GlobalScheme(); scheme != "" {
+			schemes = []string{scheme}
+		}
+	}
+
+	for _, scheme := range schemes {
+		securityScheme, ok := b.generator.securityScheme(scheme)
+		if !ok {
+			continue
+		}
+		b.securitySchemes[scheme] = securityScheme
+		operation.Security = append(operation.Security, SecurityRequirement{scheme: {}})
+	}
+}
+
+// finish 写入组件与标签
+func (b *documentBuilder) finish() {
+	if len(b.registry.schemas) > 0 {
+		b.doc.Components.Schemas = b.registry.schemas
+	}
+	if len(b.securitySchemes) > 0 {
+		b.doc.Components.SecuritySchemes = b.securitySchemes
+	}
+
+	slices.Sort(b.tags)
+	for _, tag := range b.tags {
+		b.doc.Tags = append(b.doc.Tags, Tag{Name: tag})
+	}
+}
+
+var problemType = reflect.TypeOf(problem.Details{})
+
+// supportsVersion 路由是否支持指定版本, 包含已弃用的版本
+func supportsVersion(route *web.RouteConfig, version string) bool {
+	return containsVersion(route.ApiVersions, version) || containsVersion(route.DeprecatedApiVersions, version)
+}
+
+// containsVersion 规范化后比较版本
+func containsVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if versioning.Normalize(v) == version {
+			return true
+		}
+	}
+	return false
+}
+
+// requestStruct 返回请求模型的结构体类型, 非结构体时返回 nil
+func requestStruct(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// openapiPath 将 gin 路由模板转换为 OpenAPI 路径, 返回路径参数
+func openapiPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// defaultTag 未配置标签时以路径的最后一个静态段作为标签, 如 /api/users/:id 为 users
+func defaultTag(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segment := segments[i]; segment != "" && segment[0] != ':' && segment[0] != '*' {
+			return segment
+		}
+	}
+	return ""
+}
+
+// statusDescription 状态码描述
+func statusDescription(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+	return strconv.Itoa(status)
+}
@@ -0,0 +1,83 @@
+package openapi
+
+import "github.com/xiaohangshu-dev/go-workit/pkg/webapp/versioning"
+
+// UI 文档页面类型
+type UI string
+
+const (
+	SwaggerUI UI = "swagger-ui"
+	Redoc     UI = "redoc"
+	NoUI      UI = "none"
+)
+
+// DefaultDocument 默认文档名称, 未添加文档时使用
+const DefaultDocument = "v1"
+
+// Info 文档信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Options OpenAPI 文档配置
+type Options struct {
+	Path            string                     // 文档地址前缀, 文档地址为 {Path}/{name}.json, 第一个文档同时提供于 {Path}.json
+	UIPath          string                     // 文档页面地址
+	UI              UI                         // 文档页面类型
+	SwaggerUIURL    string                     // Swagger UI 静态资源地址, 为空时使用内置资源
+	RedocURL        string                     // Redoc 脚本地址, 启用 CSP 时需在 script-src 中允许该地址
+	Servers         []string                   // 服务地址
+	securitySchemes map[string]*SecurityScheme // 鉴权方案文档 (scheme -> 描述)
+	documents       []document
+}
+
+// document 文档名称与信息
+type document struct {
+	name       string
+	info       Info
+	apiVersion string // 文档对应的 API 版本, 为空时包含全部版本的路由
+}
+
+// NewOptions 返回默认配置
+func NewOptions() *Options {
+	return &Options{
+		Path:            "/openapi",
+		UIPath:          "/docs",
+		UI:              SwaggerUI,
+		RedocURL:        "https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles/redoc.standalone.js",
+		securitySchemes: make(map[string]*SecurityScheme),
+	}
+}
+
+// AddDocument 添加文档, 路由通过 WithGroupName 归入文档, 未指定文档的路由出现在全部文档中
+func (o *Options) AddDocument(name string, info Info) *Options {
+	for _, doc := range o.documents {
+		if doc.name == name {
+			panic("openapi document with name " + name + " already exists")
+		}
+	}
+
+	o.documents = append(o.documents, document{name: name, info: info})
+	return o
+}
+
+// AddVersionDocument 添加 API 版本文档, 文档名称为 v{version}, 包含支持该版本与版本无关的路由
+// info.Version 为空时使用 API 版本
+func (o *Options) AddVersionDocument(version string, info Info) *Options {
+	version = versioning.Normalize(version)
+	if info.Version == "" {
+		info.Version = version
+	}
+
+	o.AddDocument("v"+version, info)
+	o.documents[len(o.documents)-1].apiVersion = version
+	return o
+}
+
+// AddSecurityScheme 配置鉴权方案的文档描述, 未配置时按鉴权类型生成, 无法识别的类型不出现在文档中
+func (o *Options) AddSecurityScheme(scheme string, securityScheme *SecurityScheme) *Options {
+	o.securitySchemes[scheme] = securityScheme
+	return o
+}
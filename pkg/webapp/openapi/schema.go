@@ -0,0 +1,351 @@
+package openapi
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema (2020-12)
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // 类型, 可为空值时为 [类型, "null"]
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+	unsafeName     = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	qualifier      = regexp.MustCompile(`[A-Za-z0-9_./-]*\.`)
+)
+
+// schemaRegistry 生成结构体的组件 Schema, 同一类型只生成一次
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	aliases map[reflect.Type]string // 指定组件名称的类型
+}
+
+func newSchemaRegistry(aliases map[reflect.Type]string) *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+		aliases: aliases,
+	}
+}
+
+// schema 生成类型的 Schema, 结构体生成为组件并返回引用
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "nanoseconds"}
+	case rawMessageType:
+		return &Schema{}
+	case fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		return r.component(t)
+	}
+
+	// interface 等任意类型
+	return &Schema{}
+}
+
+// component 生成结构体组件并返回引用, 匿名结构体直接内联
+func (r *schemaRegistry) component(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return r.object(t, func(reflect.StructField) bool { return true })
+	}
+
+	if name, ok := r.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := r.componentName(t)
+	r.names[t] = name
+	// 先占位, 避免递归类型无限展开
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.object(t, func(reflect.StructField) bool { return true })
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName 组件名称, 泛型类型参数去除包路径, 如 Page[pkg.User] 为 Page_User; 不同包中的同名类型追加包名区分
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	if alias, ok := r.aliases[t]; ok {
+		return alias
+	}
+
+	name := qualifier.ReplaceAllString(t.Name(), "")
+	name = unsafeName.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+
+	if _, exists := r.schemas[name]; !exists {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	qualified := unsafeName.ReplaceAllString(pkg, "_") + "." + name
+	candidate := qualified
+	for i := 2; ; i++ {
+		if _, exists := r.schemas[candidate]; !exists {
+			return candidate
+		}
+		candidate = qualified + strconv.Itoa(i)
+	}
+}
+
+// object 生成结构体的对象 Schema, include 过滤参与生成的字段
+func (r *schemaRegistry) object(t reflect.Type, include func(field reflect.StructField) bool) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, field := range structFields(t) {
+		if !include(field) {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		property := r.schema(field.Type)
+		applyFieldTags(property, field)
+		schema.Properties[name] = property
+
+		if isRequired(field) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// structFields 返回结构体的导出字段, 匿名嵌入的结构体字段展开
+func structFields(t reflect.Type) []reflect.StructField {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, structFields(embedded)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// jsonName 字段的 JSON 名称
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	if name := strings.SplitN(tag, ",", 2)[0]; name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+// tagName 字段在指定标签中声明的名称
+func tagName(field reflect.StructField, tag string) string {
+	name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// isRequired 字段是否必填
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range validateRules(field) {
+		if rule == "required" {
+			return true
+		}
+	}
+	return strings.Contains(field.Tag.Get("binding"), "required")
+}
+
+// validateRules 字段的顶层校验规则, dive 之后的规则作用于元素, 不参与生成
+func validateRules(field reflect.StructField) []string {
+	tag := field.Tag.Get("validate")
+	if tag == "" {
+		return nil
+	}
+
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		if rule == "dive" {
+			return rules[:i]
+		}
+	}
+	return rules
+}
+
+// applyFieldTags 按 description、example 与 validate 标签补充 Schema 约束
+func applyFieldTags(schema *Schema, field reflect.StructField) {
+	if description := field.Tag.Get("description"); description != "" {
+		schema.Description = description
+	}
+	if schema.Ref != "" {
+		// 引用的组件由其自身描述约束, 只补充描述
+		return
+	}
+
+	if example := field.Tag.Get("example"); example != "" {
+		schema.Examples = []any{example}
+	}
+
+	for _, rule := range validateRules(field) {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "email":
+			schema.Format = "email"
+		case "url", "uri", "http_url":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "datetime":
+			schema.Format = "date-time"
+		case "ip", "ipv4":
+			schema.Format = "ipv4"
+		case "ipv6":
+			schema.Format = "ipv6"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, enumValue(schema, option))
+			}
+		case "min", "gte":
+			applyBound(schema, value, true)
+		case "max", "lte":
+			applyBound(schema, value, false)
+		case "len":
+			applyBound(schema, value, true)
+			applyBound(schema, value, false)
+		}
+	}
+}
+
+// applyBound 按 Schema 类型设置长度、数量或数值边界
+func applyBound(schema *Schema, value string, lower bool) {
+	switch schema.Type {
+	case "string":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.MinLength = &n
+		} else {
+			schema.MaxLength = &n
+		}
+	case "array":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	case "integer", "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+	}
+}
+
+// enumValue 按 Schema 类型转换枚举值
+func enumValue(schema *Schema, value string) any {
+	switch schema.Type {
+	case "integer":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
@@ -0,0 +1,140 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	texttemplate "text/template"
+
+	swaggerFiles "github.com/swaggo/files"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// swaggerUITemplate Swagger UI 页面, 多个文档时可在页面顶部切换
+// 页面不包含内联脚本, 在默认的 CSP (default-src 'self') 下可正常加载
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>API Docs</title>
+  <link rel="stylesheet" href="{{.AssetsURL}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="{{.AssetsURL}}/swagger-ui-bundle.js"></script>
+  <script src="{{.AssetsURL}}/swagger-ui-standalone-preset.js"></script>
+  <script src="{{.ScriptURL}}"></script>
+</body>
+</html>
+`))
+
+// swaggerUIScript Swagger UI 启动脚本
+var swaggerUIScript = texttemplate.Must(texttemplate.New("swagger-ui.js").Parse(`window.ui = SwaggerUIBundle({
+  urls: {{.Documents}},
+  dom_id: "#swagger-ui",
+  deepLinking: true,
+  presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+  layout: "StandaloneLayout"
+});
+`))
+
+// redocTemplate Redoc 页面, 通过 ?document= 查询参数选择文档
+var redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>API Docs</title>
+</head>
+<body>
+  <div id="redoc"></div>
+  <script src="{{.AssetsURL}}"></script>
+  <script src="{{.ScriptURL}}"></script>
+</body>
+</html>
+`))
+
+// redocScript Redoc 启动脚本
+var redocScript = texttemplate.Must(texttemplate.New("redoc.js").Parse(`var documents = {{.Documents}};
+var name = new URLSearchParams(window.location.search).get("document");
+var selected = documents.find(function (doc) { return doc.name === name; }) || documents[0];
+Redoc.init(selected.url, {}, document.getElementById("redoc"));
+`))
+
+// uiDocument 页面中的文档
+type uiDocument struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// renderUI 生成文档页面
+func renderUI(opts *Options) []byte {
+	data := struct {
+		AssetsURL string
+		ScriptURL string
+	}{
+		AssetsURL: uiAssetsURL(opts),
+		ScriptURL: opts.UIPath + web.OpenApiUIScriptPath,
+	}
+
+	tmpl := swaggerUITemplate
+	if opts.UI == Redoc {
+		tmpl = redocTemplate
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
+
+// renderUIScript 生成文档页面的启动脚本
+func renderUIScript(opts *Options, documents []string) []byte {
+	list := make([]uiDocument, 0, len(documents))
+	for _, name := range documents {
+		list = append(list, uiDocument{Name: name, URL: opts.Path + "/" + name + ".json"})
+	}
+
+	encoded, err := json.Marshal(list)
+	if err != nil {
+		return []byte(err.Error())
+	}
+	data := struct {
+		Documents string
+	}{
+		Documents: string(encoded),
+	}
+
+	tmpl := swaggerUIScript
+	if opts.UI == Redoc {
+		tmpl = redocScript
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return []byte(err.Error())
+	}
+	return buf.Bytes()
+}
+
+// uiAssetsURL 返回页面静态资源地址, 未配置 SwaggerUIURL 时使用内置资源
+func uiAssetsURL(opts *Options) string {
+	if opts.UI == Redoc {
+		return opts.RedocURL
+	}
+	if opts.SwaggerUIURL != "" {
+		return opts.SwaggerUIURL
+	}
+	return opts.UIPath + web.OpenApiUIAssetsPath
+}
+
+// uiFiles 返回内置的 Swagger UI 静态资源, 使用外部资源时返回 nil
+func uiFiles(opts *Options) http.FileSystem {
+	if opts.UI != SwaggerUI || opts.SwaggerUIURL != "" {
+		return nil
+	}
+	return swaggerFiles.HTTP
+}
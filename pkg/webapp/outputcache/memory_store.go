@@ -0,0 +1,76 @@
+package outputcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/tools/cache"
+)
+
+// MemoryStore 内存缓存存储, 仅在单个实例内有效
+type MemoryStore struct {
+	cache *cache.MemoryCache
+	mu    sync.Mutex
+	tags  map[string]map[string]time.Time // 标签关联的缓存键及其过期时间
+}
+
+// NewMemoryStore 创建内存缓存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		cache: cache.NewMemoryCache(),
+		tags:  make(map[string]map[string]time.Time),
+	}
+}
+
+// Get 读取缓存
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := s.cache.Get(key)
+	if !ok {
+		return nil, nil
+	}
+	return value.([]byte), nil
+}
+
+// Set 写入缓存并关联标签
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, tags []string, ttl time.Duration) error {
+	s.cache.Set(key, value, ttl)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	expireAt := now.Add(ttl)
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]time.Time)
+			s.tags[tag] = keys
+		}
+		keys[key] = expireAt
+
+		// 顺带清理已过期的键, 避免标签索引无限增长
+		for k, exp := range keys {
+			if exp.Before(now) {
+				delete(keys, k)
+			}
+		}
+	}
+	return nil
+}
+
+// EvictByTag 清除标签关联的全部缓存
+func (s *MemoryStore) EvictByTag(ctx context.Context, tag string) error {
+	s.mu.Lock()
+	keys := s.tags[tag]
+	delete(s.tags, tag)
+	s.mu.Unlock()
+
+	for key := range keys {
+		s.cache.Delete(key)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package outputcache
+
+import (
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// Options 输出缓存配置
+type Options struct {
+	DefaultExpire time.Duration // 默认策略的缓存时间
+	MaxBodySize   int64         // 可缓存的最大响应体字节数, 超过时不缓存
+	policies      map[string]*web.OutputCachePolicy
+	store         any
+}
+
+// NewOptions 返回默认配置, 默认策略缓存 60 秒并按全部查询参数区分, 默认使用内存存储
+func NewOptions() *Options {
+	return &Options{
+		DefaultExpire: time.Minute,
+		MaxBodySize:   1 << 20,
+		policies:      make(map[string]*web.OutputCachePolicy),
+	}
+}
+
+// AddPolicy 添加缓存策略, 未设置缓存时间时使用 DefaultExpire
+// 未设置 VaryByQuery 时按全部查询参数区分, 设置为 []string{} 时不区分查询参数
+func (o *Options) AddPolicy(name string, fn func(policy *web.OutputCachePolicy)) *Options {
+	if _, exists := o.policies[name]; exists {
+		panic("output cache policy with name " + name + " already exists")
+	}
+
+	policy := &web.OutputCachePolicy{}
+	fn(policy)
+	o.policies[name] = policy
+	return o
+}
+
+// UseStore 使用自定义缓存存储, constructor 为返回 web.OutputCacheStore 实现的构造函数, 参数由容器注入
+// 如 UseStore(outputcache.NewRedisStore) 使用默认 Redis 客户端
+func (o *Options) UseStore(constructor any) *Options {
+	o.store = constructor
+	return o
+}
+
+// Store 返回自定义缓存存储的构造函数, 未配置时返回 nil
+func (o *Options) Store() any {
+	return o.store
+}
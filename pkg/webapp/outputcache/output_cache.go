@@ -0,0 +1,47 @@
+package outputcache
+
+import (
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// OutputCache 输出缓存策略管理
+type OutputCache struct {
+	opts          *Options
+	defaultPolicy *web.OutputCachePolicy
+}
+
+// NewOutputCache 创建输出缓存策略管理
+func NewOutputCache(opts *Options) *OutputCache {
+	for _, policy := range opts.policies {
+		if policy.Expire <= 0 {
+			policy.Expire = opts.DefaultExpire
+		}
+		// 未设置时按全部查询参数区分, 显式设置为空切片表示不区分
+		if policy.VaryByQuery == nil {
+			policy.VaryByQuery = []string{"*"}
+		}
+	}
+
+	return &OutputCache{
+		opts: opts,
+		defaultPolicy: &web.OutputCachePolicy{
+			Expire:      opts.DefaultExpire,
+			VaryByQuery: []string{"*"},
+		},
+	}
+}
+
+// Policy 返回缓存策略, 名称为空时返回默认策略
+func (o *OutputCache) Policy(name string) (*web.OutputCachePolicy, bool) {
+	if name == "" {
+		return o.defaultPolicy, true
+	}
+
+	policy, ok := o.opts.policies[name]
+	return policy, ok
+}
+
+// MaxBodySize 返回可缓存的最大响应体字节数
+func (o *OutputCache) MaxBodySize() int64 {
+	return o.opts.MaxBodySize
+}
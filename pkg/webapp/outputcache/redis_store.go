@@ -0,0 +1,65 @@
+package outputcache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisKeyPrefix Redis 缓存键前缀
+const redisKeyPrefix = "outputcache:"
+
+// RedisStore Redis 缓存存储, 多个实例共享缓存与标签
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore 创建 Redis 缓存存储
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Get 读取缓存
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Set 写入缓存, 标签以集合保存关联的缓存键, 集合的过期时间不短于其中的缓存
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, tags []string, ttl time.Duration) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisKeyPrefix+key, value, ttl)
+		for _, tag := range tags {
+			tagKey := redisKeyPrefix + "tag:" + tag
+			pipe.SAdd(ctx, tagKey, key)
+			if pttl := s.client.PTTL(ctx, tagKey).Val(); pttl < ttl {
+				pipe.PExpire(ctx, tagKey, ttl)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// EvictByTag 清除标签关联的全部缓存
+func (s *RedisStore) EvictByTag(ctx context.Context, tag string) error {
+	tagKey := redisKeyPrefix + "tag:" + tag
+
+	keys, err := s.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return err
+	}
+
+	toDelete := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		toDelete = append(toDelete, redisKeyPrefix+key)
+	}
+	toDelete = append(toDelete, tagKey)
+
+	return s.client.Del(ctx, toDelete...).Err()
+}
@@ -0,0 +1,70 @@
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// ContentType Problem Details 响应类型(RFC 7807)
+const ContentType = "application/problem+json"
+
+// Details RFC 7807 Problem Details 响应体
+type Details struct {
+	Type       string         `json:"type"`               // 问题类型 URI, 默认 about:blank
+	Title      string         `json:"title"`              // 问题简述, 已本地化
+	Status     int            `json:"status"`             // HTTP 状态码
+	Detail     string         `json:"detail,omitempty"`   // 问题详情
+	Instance   string         `json:"instance,omitempty"` // 发生问题的请求路径
+	TraceID    string         `json:"traceId,omitempty"`  // 链路追踪 ID
+	Errors     []FieldError   `json:"errors,omitempty"`   // 字段校验错误
+	Extensions map[string]any `json:"-"`                  // 扩展成员, 与标准成员平铺输出
+}
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field   string `json:"field"`          // 字段名
+	Message string `json:"message"`        // 错误信息
+	Code    string `json:"code,omitempty"` // 错误码, 如 required、max
+}
+
+// MarshalJSON 扩展成员按 RFC 7807 追加在标准成员之后, 不覆盖标准成员
+func (d Details) MarshalJSON() ([]byte, error) {
+	type details Details
+	data, err := json.Marshal(details(d))
+	if err != nil || len(d.Extensions) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(d.Extensions))
+	for key := range d.Extensions {
+		if _, ok := standardMembers[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, key := range keys {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(d.Extensions[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// standardMembers 标准成员, 扩展成员不能与之重名
+var standardMembers = map[string]struct{}{
+	"type": {}, "title": {}, "status": {}, "detail": {}, "instance": {}, "traceId": {}, "errors": {},
+}
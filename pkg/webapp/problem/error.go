@@ -0,0 +1,166 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Error 携带 HTTP 语义的错误, 由异常处理中间件渲染为 Problem Details
+type Error struct {
+	Status     int            // HTTP 状态码
+	Type       string         // 问题类型 URI, 为空时为 about:blank
+	TitleID    string         // 标题的国际化消息 ID
+	Title      string         // 默认标题, 未找到翻译时使用
+	Detail     string         // 问题详情
+	Errors     []FieldError   // 字段校验错误
+	Extensions map[string]any // 扩展成员
+	Headers    http.Header    // 附加响应头, 如 Retry-After
+	Err        error          // 原始错误
+}
+
+// New 创建指定状态码的错误, 标题默认为状态码的标准描述
+func New(status int, detail string) *Error {
+	return &Error{
+		Status:  status,
+		TitleID: "problem." + strconv.Itoa(status),
+		Title:   http.StatusText(status),
+		Detail:  detail,
+	}
+}
+
+func newError(status int, titleID, detail string) *Error {
+	e := New(status, detail)
+	e.TitleID = titleID
+	return e
+}
+
+// BadRequest 请求不合法 400
+func BadRequest(detail string) *Error {
+	return newError(http.StatusBadRequest, "problem.bad_request", detail)
+}
+
+// Validation 请求参数校验失败 400
+func Validation(fields ...FieldError) *Error {
+	e := newError(http.StatusBadRequest, "problem.validation", "")
+	e.Title = "One or more validation errors occurred."
+	e.Errors = fields
+	return e
+}
+
+// Unauthorized 未认证 401
+func Unauthorized(detail string) *Error {
+	return newError(http.StatusUnauthorized, "problem.unauthorized", detail)
+}
+
+// Forbidden 无权限 403
+func Forbidden(detail string) *Error {
+	return newError(http.StatusForbidden, "problem.forbidden", detail)
+}
+
+// NotFound 资源不存在 404
+func NotFound(detail string) *Error {
+	return newError(http.StatusNotFound, "problem.not_found", detail)
+}
+
+// Conflict 资源冲突 409
+func Conflict(detail string) *Error {
+	return newError(http.StatusConflict, "problem.conflict", detail)
+}
+
+// PreconditionFailed 条件请求校验失败 412, 用于 If-Match 等乐观并发控制
+func PreconditionFailed(detail string) *Error {
+	return newError(http.StatusPreconditionFailed, "problem.precondition_failed", detail)
+}
+
+// PayloadTooLarge 请求体过大 413
+func PayloadTooLarge(detail string) *Error {
+	return newError(http.StatusRequestEntityTooLarge, "problem.payload_too_large", detail)
+}
+
+// UnsupportedMediaType 不支持的媒体类型或编码 415
+func UnsupportedMediaType(detail string) *Error {
+	return newError(http.StatusUnsupportedMediaType, "problem.unsupported_media_type", detail)
+}
+
+// UnprocessableEntity 请求格式正确但无法处理 422
+func UnprocessableEntity(detail string) *Error {
+	return newError(http.StatusUnprocessableEntity, "problem.unprocessable_entity", detail)
+}
+
+// TooManyRequests 请求过于频繁 429, retryAfter 为建议的重试秒数
+func TooManyRequests(retryAfter int) *Error {
+	e := newError(http.StatusTooManyRequests, "problem.too_many_requests", "")
+	e.Headers = http.Header{"Retry-After": []string{strconv.Itoa(retryAfter)}}
+	e.Extensions = map[string]any{"retryAfter": retryAfter}
+	return e
+}
+
+// Internal 服务器内部错误 500, 原始错误仅在开发环境输出
+func Internal(err error) *Error {
+	e := newError(http.StatusInternalServerError, "problem.internal", "")
+	e.Err = err
+	return e
+}
+
+// BadGateway 上游服务响应无效或无法连接 502
+func BadGateway(detail string) *Error {
+	return newError(http.StatusBadGateway, "problem.bad_gateway", detail)
+}
+
+// ServiceUnavailable 服务暂不可用 503
+func ServiceUnavailable(detail string) *Error {
+	return newError(http.StatusServiceUnavailable, "problem.service_unavailable", detail)
+}
+
+// GatewayTimeout 上游服务响应超时 504
+func GatewayTimeout(detail string) *Error {
+	return newError(http.StatusGatewayTimeout, "problem.gateway_timeout", detail)
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	msg := strconv.Itoa(e.Status) + " " + e.Title
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap 返回原始错误
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithError 设置原始错误
+func (e *Error) WithError(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// WithType 设置问题类型 URI
+func (e *Error) WithType(uri string) *Error {
+	e.Type = uri
+	return e
+}
+
+// WithExtension 添加扩展成员
+func (e *Error) WithExtension(key string, value any) *Error {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// As 从错误链中查找 *Error
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
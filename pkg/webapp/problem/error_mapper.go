@@ -0,0 +1,57 @@
+package problem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// ErrorMapper 将错误映射为 Problem Details 错误
+type ErrorMapper struct {
+	typeBaseURI string
+	mappers     []Mapper
+}
+
+// NewErrorMapper 创建错误映射
+func NewErrorMapper(opts *Options) *ErrorMapper {
+	return &ErrorMapper{
+		typeBaseURI: opts.TypeBaseURI,
+		mappers:     opts.mappers,
+	}
+}
+
+// Map 依次执行自定义映射与内置映射, 未识别的错误映射为 500
+func (m *ErrorMapper) Map(err error) *Error {
+	mapped := m.mapError(err)
+
+	// 复制一份, 避免修改作为哨兵值复用的错误
+	e := *mapped
+	if e.Type == "" && m.typeBaseURI != "" {
+		e.Type = m.typeBaseURI + strconv.Itoa(e.Status)
+	}
+	return &e
+}
+
+func (m *ErrorMapper) mapError(err error) *Error {
+	for _, mapper := range m.mappers {
+		if e, ok := mapper(err); ok && e != nil {
+			return e
+		}
+	}
+
+	if e, ok := As(err); ok {
+		return e
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return PayloadTooLarge("").WithError(err)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(http.StatusGatewayTimeout, "").WithError(err)
+	}
+
+	return Internal(err)
+}
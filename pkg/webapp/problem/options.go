@@ -0,0 +1,21 @@
+package problem
+
+// Mapper 自定义错误映射, 返回 false 时交由下一个映射处理
+type Mapper func(err error) (*Error, bool)
+
+// Options Problem Details 配置
+type Options struct {
+	TypeBaseURI string // 问题类型 URI 前缀, 设置后 type 为前缀 + 状态码, 为空时为 about:blank
+	mappers     []Mapper
+}
+
+// NewOptions 返回默认配置
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// AddMapper 添加自定义错误映射, 按添加顺序优先于内置映射执行
+func (o *Options) AddMapper(mapper Mapper) *Options {
+	o.mappers = append(o.mappers, mapper)
+	return o
+}
@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// balancer 负载均衡策略, 从可用目标中选择一个
+type balancer interface {
+	pick(candidates []*destination) *destination
+}
+
+// newBalancer 按名称创建负载均衡策略, 为空时使用轮询
+func newBalancer(name string) (balancer, error) {
+	switch name {
+	case "", RoundRobin:
+		return &roundRobin{}, nil
+	case LeastRequests:
+		return &leastRequests{}, nil
+	}
+	return nil, fmt.Errorf("unknown load balancing policy %q", name)
+}
+
+// roundRobin 轮询
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (b *roundRobin) pick(candidates []*destination) *destination {
+	return candidates[(b.next.Add(1)-1)%uint64(len(candidates))]
+}
+
+// leastRequests 选择处理中请求最少的目标, 请求数相同时轮流选择
+type leastRequests struct {
+	next atomic.Uint64
+}
+
+func (b *leastRequests) pick(candidates []*destination) *destination {
+	offset := int((b.next.Add(1) - 1) % uint64(len(candidates)))
+
+	var selected *destination
+	for i := range candidates {
+		d := candidates[(offset+i)%len(candidates)]
+		if selected == nil || d.inflight.Load() < selected.inflight.Load() {
+			selected = d
+		}
+	}
+	return selected
+}
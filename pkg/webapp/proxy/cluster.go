@@ -0,0 +1,311 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// errNoDestination 集群没有可用的目标
+var errNoDestination = errors.New("no healthy destination available")
+
+// destination 上游目标及其健康状态
+type destination struct {
+	url            *url.URL
+	inflight       atomic.Int64 // 处理中的请求数
+	activeHealthy  atomic.Bool  // 主动健康检查结果
+	failures       atomic.Int32 // 被动健康检查连续失败次数
+	unhealthyUntil atomic.Int64 // 被动健康检查暂停使用的截止时间, UnixNano
+}
+
+// available 目标是否可参与负载均衡
+func (d *destination) available(now time.Time) bool {
+	return d.activeHealthy.Load() && now.UnixNano() >= d.unhealthyUntil.Load()
+}
+
+// cluster 上游集群, 实现 http.RoundTripper, 负责选择目标、重试与被动健康检查
+type cluster struct {
+	name         string
+	opts         *ClusterOptions
+	destinations []*destination
+	balancer     balancer
+	transport    http.RoundTripper
+	logger       *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newCluster 创建上游集群
+func newCluster(name string, opts *ClusterOptions, logger *zap.Logger) (*cluster, error) {
+	if len(opts.Destinations) == 0 {
+		return nil, fmt.Errorf("proxy cluster %s: no destinations", name)
+	}
+
+	b, err := newBalancer(opts.LoadBalancing)
+	if err != nil {
+		return nil, fmt.Errorf("proxy cluster %s: %w", name, err)
+	}
+
+	destinations := make([]*destination, 0, len(opts.Destinations))
+	for _, address := range opts.Destinations {
+		u, err := url.Parse(address)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("proxy cluster %s: invalid destination %q", name, address)
+		}
+		d := &destination{url: u}
+		d.activeHealthy.Store(true)
+		destinations = append(destinations, d)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = opts.Timeout
+	transport.MaxIdleConnsPerHost = 100
+
+	return &cluster{
+		name:         name,
+		opts:         opts,
+		destinations: destinations,
+		balancer:     b,
+		transport:    transport,
+		logger:       logger,
+	}, nil
+}
+
+// RoundTrip 选择目标转发请求, 连接失败或响应状态码需要重试时换用其他目标
+func (c *cluster) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := c.opts.Retry.Attempts > 0 && isIdempotent(req.Method) &&
+		(req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	var tried []*destination
+	for attempt := 0; ; attempt++ {
+		d := c.pick(tried)
+		if d == nil {
+			return nil, errNoDestination
+		}
+
+		out := req.Clone(req.Context())
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out.Body = body
+		}
+		rewriteURL(out.URL, d.url)
+
+		d.inflight.Add(1)
+		resp, err := c.transport.RoundTrip(out)
+		c.report(d, resp, err)
+
+		if err != nil {
+			d.inflight.Add(-1)
+		} else if resp.StatusCode == http.StatusSwitchingProtocols {
+			// 协议升级的响应体为双向连接, 不再计入处理中请求
+			d.inflight.Add(-1)
+		} else {
+			resp.Body = &trackedBody{ReadCloser: resp.Body, destination: d}
+		}
+
+		if !retryable || attempt >= c.opts.Retry.Attempts || req.Context().Err() != nil ||
+			(err == nil && !slices.Contains(c.opts.Retry.StatusCodes, resp.StatusCode)) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+		}
+		c.logger.Debug("retrying proxy request",
+			zap.String("cluster", c.name),
+			zap.String("destination", d.url.Host),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
+		tried = append(tried, d)
+	}
+}
+
+// pick 选择可用目标, 优先选择未尝试过的目标
+func (c *cluster) pick(tried []*destination) *destination {
+	now := time.Now()
+	candidates := make([]*destination, 0, len(c.destinations))
+	for _, d := range c.destinations {
+		if d.available(now) && !slices.Contains(tried, d) {
+			candidates = append(candidates, d)
+		}
+	}
+	if len(candidates) == 0 {
+		for _, d := range c.destinations {
+			if d.available(now) {
+				candidates = append(candidates, d)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return c.balancer.pick(candidates)
+}
+
+// report 记录被动健康检查结果
+func (c *cluster) report(d *destination, resp *http.Response, err error) {
+	passive := c.opts.HealthCheck.Passive
+	if !passive.Enabled {
+		return
+	}
+
+	failed := err != nil || resp.StatusCode == http.StatusBadGateway ||
+		resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout
+	if !failed {
+		d.failures.Store(0)
+		return
+	}
+
+	if int(d.failures.Add(1)) >= passive.MaxFailures {
+		d.failures.Store(0)
+		d.unhealthyUntil.Store(time.Now().Add(passive.ReactivationPeriod).UnixNano())
+		c.logger.Warn("proxy destination marked unhealthy",
+			zap.String("cluster", c.name),
+			zap.String("destination", d.url.Host),
+			zap.Duration("reactivation", passive.ReactivationPeriod))
+	}
+}
+
+// start 启动主动健康检查
+func (c *cluster) start() {
+	active := c.opts.HealthCheck.Active
+	if !active.Enabled || active.Interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(active.Interval)
+		defer ticker.Stop()
+
+		for {
+			c.probeAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop 停止主动健康检查
+func (c *cluster) stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+}
+
+// probeAll 并发检查全部目标
+func (c *cluster) probeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, d := range c.destinations {
+		wg.Add(1)
+		go func(d *destination) {
+			defer wg.Done()
+
+			healthy := c.probe(ctx, d)
+			if d.activeHealthy.Swap(healthy) != healthy && ctx.Err() == nil {
+				c.logger.Info("proxy destination health changed",
+					zap.String("cluster", c.name),
+					zap.String("destination", d.url.Host),
+					zap.Bool("healthy", healthy))
+			}
+		}(d)
+	}
+	wg.Wait()
+}
+
+// probe 请求目标的健康检查地址
+func (c *cluster) probe(ctx context.Context, d *destination) bool {
+	active := c.opts.HealthCheck.Active
+	if active.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, active.Timeout)
+		defer cancel()
+	}
+
+	u := *d.url
+	u.Path = joinURLPath(d.url.Path, active.Path)
+	u.RawPath = ""
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// trackedBody 响应体关闭时结束处理中请求的计数
+type trackedBody struct {
+	io.ReadCloser
+	destination *destination
+	once        sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() { b.destination.inflight.Add(-1) })
+	return b.ReadCloser.Close()
+}
+
+// rewriteURL 将请求地址改为目标地址, 目标路径作为前缀
+func rewriteURL(u, target *url.URL) {
+	u.Scheme = target.Scheme
+	u.Host = target.Host
+	u.Path = joinURLPath(target.Path, u.Path)
+	u.RawPath = ""
+	if target.RawQuery != "" {
+		if u.RawQuery == "" {
+			u.RawQuery = target.RawQuery
+		} else {
+			u.RawQuery = target.RawQuery + "&" + u.RawQuery
+		}
+	}
+}
+
+// joinURLPath 拼接路径, 保证两段之间只有一个斜杠
+func joinURLPath(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	return strings.TrimSuffix(a, "/") + "/" + strings.TrimPrefix(b, "/")
+}
+
+// isIdempotent 请求方法是否幂等
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// 负载均衡策略
+const (
+	RoundRobin    = "round_robin"    // 轮询
+	LeastRequests = "least_requests" // 选择处理中请求最少的目标
+)
+
+// ClusterOptions 上游集群配置
+type ClusterOptions struct {
+	Destinations  []string           `mapstructure:"destinations"`   // 目标地址, 如 http://10.0.0.1:8080, 可包含路径前缀
+	LoadBalancing string             `mapstructure:"load_balancing"` // 负载均衡策略
+	Timeout       time.Duration      `mapstructure:"timeout"`        // 等待上游响应头的超时时间
+	HealthCheck   HealthCheckOptions `mapstructure:"health_check"`
+	Retry         RetryOptions       `mapstructure:"retry"`
+}
+
+// HealthCheckOptions 健康检查配置, 主动与被动检查均判定健康的目标才参与负载均衡
+type HealthCheckOptions struct {
+	Active  ActiveHealthCheckOptions  `mapstructure:"active"`
+	Passive PassiveHealthCheckOptions `mapstructure:"passive"`
+}
+
+// ActiveHealthCheckOptions 主动健康检查, 定期请求目标的健康检查地址, 2xx 为健康
+type ActiveHealthCheckOptions struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"` // 检查间隔
+	Timeout  time.Duration `mapstructure:"timeout"`  // 单次检查超时
+	Path     string        `mapstructure:"path"`     // 健康检查路径
+}
+
+// PassiveHealthCheckOptions 被动健康检查, 转发连续失败达到阈值时暂停使用目标, 到期后恢复
+type PassiveHealthCheckOptions struct {
+	Enabled            bool          `mapstructure:"enabled"`
+	MaxFailures        int           `mapstructure:"max_failures"`        // 连续失败次数阈值, 连接失败与 502、503、504 响应计为失败
+	ReactivationPeriod time.Duration `mapstructure:"reactivation_period"` // 暂停使用的时长
+}
+
+// RetryOptions 重试配置, 仅重试幂等请求, 每次重试优先选择未尝试过的目标
+type RetryOptions struct {
+	Attempts    int   `mapstructure:"attempts"`      // 最大重试次数, 0 为不重试
+	StatusCodes []int `mapstructure:"status_codes"`  // 需要重试的响应状态码, 连接失败总是重试
+	MaxBodySize int64 `mapstructure:"max_body_size"` // 可重试的最大请求体字节数, 请求体需缓冲以便重发
+}
+
+// RouteOptions 配置文件中的代理路由, 路由前缀下的全部请求转发到集群
+type RouteOptions struct {
+	Path           string             `mapstructure:"path"`
+	Cluster        string             `mapstructure:"cluster"`
+	Schemes        []string           `mapstructure:"schemes"`
+	Policies       []string           `mapstructure:"policies"`
+	RateLimiter    []string           `mapstructure:"rate_limiter"`
+	AllowAnonymous bool               `mapstructure:"allow_anonymous"`
+	Timeout        time.Duration      `mapstructure:"timeout"`
+	CorsPolicy     string             `mapstructure:"cors_policy"`
+	Transforms     web.ProxyTransform `mapstructure:"transforms"`
+}
+
+// Options 反向代理配置
+type Options struct {
+	clusters map[string]*ClusterOptions
+	routes   []RouteOptions
+}
+
+// NewOptions 返回默认配置
+func NewOptions() *Options {
+	return &Options{
+		clusters: make(map[string]*ClusterOptions),
+	}
+}
+
+// newClusterOptions 返回集群的默认配置
+func newClusterOptions() *ClusterOptions {
+	return &ClusterOptions{
+		LoadBalancing: RoundRobin,
+		Timeout:       30 * time.Second,
+		HealthCheck: HealthCheckOptions{
+			Active: ActiveHealthCheckOptions{
+				Interval: 10 * time.Second,
+				Timeout:  3 * time.Second,
+				Path:     "/health",
+			},
+			Passive: PassiveHealthCheckOptions{
+				MaxFailures:        3,
+				ReactivationPeriod: 30 * time.Second,
+			},
+		},
+		Retry: RetryOptions{
+			StatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+			MaxBodySize: 1 << 20,
+		},
+	}
+}
+
+// LoadConfig 从配置 proxy.clusters.<name> 加载集群, 从 proxy.routes 加载代理路由
+func (o *Options) LoadConfig(config *viper.Viper) error {
+	for name := range config.GetStringMap("proxy.clusters") {
+		cluster := newClusterOptions()
+		if err := config.UnmarshalKey("proxy.clusters."+name, cluster); err != nil {
+			return fmt.Errorf("load proxy cluster %s config: %w", name, err)
+		}
+		o.clusters[name] = cluster
+	}
+
+	var routes []RouteOptions
+	if err := config.UnmarshalKey("proxy.routes", &routes); err != nil {
+		return fmt.Errorf("load proxy routes config: %w", err)
+	}
+	o.routes = append(o.routes, routes...)
+	return nil
+}
+
+// AddCluster 添加上游集群, 同名集群已从配置加载时在其基础上修改
+func (o *Options) AddCluster(name string, fn func(cluster *ClusterOptions)) *Options {
+	cluster, ok := o.clusters[name]
+	if !ok {
+		cluster = newClusterOptions()
+		o.clusters[name] = cluster
+	}
+	fn(cluster)
+	return o
+}
+
+// Clusters 返回全部集群配置
+func (o *Options) Clusters() map[string]*ClusterOptions {
+	return o.clusters
+}
+
+// Routes 返回配置文件中的代理路由
+func (o *Options) Routes() []RouteOptions {
+	return o.routes
+}
+
+// MapRoutes 将配置文件中的代理路由注册到应用
+func (o *Options) MapRoutes(app web.Application) {
+	for _, route := range o.routes {
+		group := app.MapGroup(route.Path).
+			WithAuthenticationScheme(route.Schemes...).
+			WithAuthorizationPolicy(route.Policies...).
+			WithRateLimiter(route.RateLimiter...)
+		if route.AllowAnonymous {
+			group.WithAllowAnonymous()
+		}
+		if route.Timeout > 0 {
+			group.WithTimeout(route.Timeout)
+		}
+		if route.CorsPolicy != "" {
+			group.WithCors(route.CorsPolicy)
+		}
+		group.MapProxy(route.Cluster, route.Transforms)
+	}
+}
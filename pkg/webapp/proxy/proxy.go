@@ -0,0 +1,156 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Proxy 反向代理, 将请求转发到上游集群
+type Proxy struct {
+	clusters map[string]*cluster
+	logger   *zap.Logger
+}
+
+// NewProxy 创建反向代理, 集群配置无效时返回错误; 主动健康检查随应用启动与停止
+func NewProxy(opts *Options, lc fx.Lifecycle, logger *zap.Logger) (*Proxy, error) {
+	p := &Proxy{
+		clusters: make(map[string]*cluster, len(opts.Clusters())),
+		logger:   logger,
+	}
+	for name, clusterOpts := range opts.Clusters() {
+		c, err := newCluster(name, clusterOpts, logger)
+		if err != nil {
+			return nil, err
+		}
+		p.clusters[name] = c
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			for _, c := range p.clusters {
+				c.start()
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			for _, c := range p.clusters {
+				c.stop()
+			}
+			return nil
+		},
+	})
+	return p, nil
+}
+
+// Forward 返回将请求转发到集群的处理函数
+// 转发时写出 X-Forwarded-For、X-Forwarded-Host 与 X-Forwarded-Proto, 原始 Authorization 等请求头原样转发
+func (p *Proxy) Forward(name string, transform web.ProxyTransform) (gin.HandlerFunc, error) {
+	c, ok := p.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("proxy cluster %s not found", name)
+	}
+
+	return func(ctx *gin.Context) {
+		upstreamPath := ctx.Request.URL.Path
+		if transform.StripPrefix {
+			upstreamPath = ctx.Param(web.ProxyPathParam)
+		}
+		upstreamPath = joinURLPath(transform.AddPrefix, upstreamPath)
+		if upstreamPath == "" {
+			upstreamPath = "/"
+		}
+
+		if err := bufferBody(ctx.Request, c.opts.Retry); err != nil {
+			web.AbortWithError(ctx, problem.BadRequest("failed to read request body").WithError(err))
+			return
+		}
+
+		rp := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetXForwarded()
+				pr.Out.URL.Path = upstreamPath
+				pr.Out.URL.RawPath = ""
+				if transform.PreserveHost {
+					pr.Out.Host = pr.In.Host
+				} else {
+					pr.Out.Host = ""
+				}
+				for _, key := range transform.RemoveRequestHeaders {
+					pr.Out.Header.Del(key)
+				}
+				for key, value := range transform.RequestHeaders {
+					pr.Out.Header.Set(key, value)
+				}
+			},
+			Transport: c,
+			ModifyResponse: func(resp *http.Response) error {
+				for _, key := range transform.RemoveResponseHeaders {
+					resp.Header.Del(key)
+				}
+				for key, value := range transform.ResponseHeaders {
+					resp.Header.Set(key, value)
+				}
+				return nil
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				p.handleError(ctx, name, err)
+			},
+		}
+		rp.ServeHTTP(ctx.Writer, ctx.Request)
+	}, nil
+}
+
+// handleError 转发失败时按原因返回 503、504 或 502, 客户端已断开时不写出响应
+func (p *Proxy) handleError(c *gin.Context, cluster string, err error) {
+	if errors.Is(c.Request.Context().Err(), context.Canceled) {
+		c.Abort()
+		return
+	}
+
+	p.logger.Warn("proxy request failed",
+		zap.String("cluster", cluster),
+		zap.String("path", c.Request.URL.Path),
+		zap.Error(err))
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errNoDestination):
+		web.AbortWithError(c, problem.ServiceUnavailable("no healthy destination in cluster "+cluster).WithError(err))
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		web.AbortWithError(c, problem.GatewayTimeout("upstream cluster "+cluster+" timed out").WithError(err))
+	default:
+		web.AbortWithError(c, problem.BadGateway("upstream cluster "+cluster+" is unavailable").WithError(err))
+	}
+}
+
+// bufferBody 可重试的请求缓冲请求体, 以便重试时重新发送; 请求体长度未知或超过限制时不缓冲, 该请求不重试
+func bufferBody(r *http.Request, retry RetryOptions) error {
+	if retry.Attempts <= 0 || !isIdempotent(r.Method) || r.Body == nil || r.Body == http.NoBody ||
+		r.ContentLength <= 0 || r.ContentLength > retry.MaxBodySize {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
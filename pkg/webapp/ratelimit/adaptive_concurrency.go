@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"container/list"
+	"math"
+	"time"
+)
+
+// AdaptiveAlgorithm 自适应并发限流算法
+type AdaptiveAlgorithm int
+
+const (
+	// AIMD 加性增、乘性减: 耗时超过阈值时按比例缩减限值, 否则逐步增加
+	AIMD AdaptiveAlgorithm = iota
+	// Gradient 梯度算法: 根据长期 RTT 与当前 RTT 的比值调整限值
+	Gradient
+)
+
+// AdaptiveConcurrencyOptions 自适应并发限流选项
+type AdaptiveConcurrencyOptions struct {
+	Algorithm    AdaptiveAlgorithm // 限流算法
+	InitialLimit int               // 初始并发限值
+	MinLimit     int               // 最小并发限值
+	MaxLimit     int               // 最大并发限值
+
+	LatencyThreshold time.Duration // 耗时超过该值视为过载 (AIMD)
+	BackoffRatio     float64       // 过载时限值的缩减比例 (AIMD)
+
+	Tolerance  float64 // 允许的延迟膨胀倍数 (Gradient)
+	Smoothing  float64 // 限值平滑系数 (Gradient)
+	LongWindow int     // 长期 RTT 的样本窗口 (Gradient)
+}
+
+// latencyObserver 需要观测请求耗时的限流器
+type latencyObserver interface {
+	ReleaseWithLatency(key string, latency time.Duration)
+}
+
+// AdaptiveConcurrencyLimiter 自适应并发限流器
+// 限值作用于整个服务而非单个分区, 根据观测到的请求耗时动态调整
+type AdaptiveConcurrencyLimiter struct {
+	baseLimiter
+	limit    float64 // 当前并发限值
+	inflight int     // 正在处理的请求数
+	longRtt  float64 // 长期 RTT (纳秒)
+	options  *AdaptiveConcurrencyOptions
+}
+
+func NewAdaptiveConcurrencyLimiter(options *AdaptiveConcurrencyOptions) *AdaptiveConcurrencyLimiter {
+	return &AdaptiveConcurrencyLimiter{
+		baseLimiter: baseLimiter{
+			queue: make(map[string]*list.List),
+		},
+		limit:   float64(options.InitialLimit),
+		options: options,
+	}
+}
+
+func (l *AdaptiveConcurrencyLimiter) TryAcquire(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inflight >= int(l.limit) {
+		if l.longRtt > 0 {
+			return false, time.Duration(l.longRtt)
+		}
+		return false, time.Millisecond * 100
+	}
+
+	l.inflight++
+	return true, 0
+}
+
+// Release 释放并发名额, 不参与限值调整
+func (l *AdaptiveConcurrencyLimiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inflight > 0 {
+		l.inflight--
+	}
+}
+
+// Refund 归还已获取的并发名额
+func (l *AdaptiveConcurrencyLimiter) Refund(key string) {
+	l.Release(key)
+}
+
+// ReleaseWithLatency 释放并发名额并根据请求耗时调整限值
+func (l *AdaptiveConcurrencyLimiter) ReleaseWithLatency(key string, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inflight := l.inflight
+	if l.inflight > 0 {
+		l.inflight--
+	}
+
+	switch l.options.Algorithm {
+	case Gradient:
+		l.gradient(float64(latency), inflight)
+	default:
+		l.aimd(latency, inflight)
+	}
+}
+
+// Limit 当前并发限值
+func (l *AdaptiveConcurrencyLimiter) Limit() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return int(l.limit)
+}
+
+// aimd 加性增、乘性减
+func (l *AdaptiveConcurrencyLimiter) aimd(latency time.Duration, inflight int) {
+	if latency > l.options.LatencyThreshold {
+		l.setLimit(l.limit * l.options.BackoffRatio)
+		return
+	}
+
+	// 并发未接近限值时说明负载不足, 不增加限值
+	if float64(inflight)*2 >= l.limit {
+		l.setLimit(l.limit + 1)
+	}
+}
+
+// gradient 梯度算法
+func (l *AdaptiveConcurrencyLimiter) gradient(rtt float64, inflight int) {
+	if rtt <= 0 {
+		return
+	}
+
+	if l.longRtt == 0 {
+		l.longRtt = rtt
+	} else {
+		l.longRtt += (rtt - l.longRtt) / float64(l.options.LongWindow)
+	}
+
+	// 长期 RTT 明显高于当前 RTT 时快速回落, 避免负载恢复后限值长期偏低
+	if l.longRtt/rtt > 2 {
+		l.longRtt *= 0.95
+	}
+
+	if float64(inflight)*2 < l.limit {
+		return
+	}
+
+	g := math.Max(0.5, math.Min(1.0, l.options.Tolerance*l.longRtt/rtt))
+	newLimit := l.limit*g + math.Sqrt(l.limit)
+	l.setLimit(l.limit*(1-l.options.Smoothing) + newLimit*l.options.Smoothing)
+}
+
+func (l *AdaptiveConcurrencyLimiter) setLimit(limit float64) {
+	l.limit = math.Max(float64(l.options.MinLimit), math.Min(float64(l.options.MaxLimit), limit))
+}
@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"slices"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// Rejection 限流拒绝信息
+type Rejection struct {
+	Policy     string        // 拒绝请求的限流策略
+	RetryAfter time.Duration // 建议的重试等待时间
+}
+
+// Chain 链式限流器
+// 全局限流器始终最先生效, 随后依次应用路由声明的限流器;
+// 任一限流器拒绝时, 之前已获取的许可会被全部归还
+type Chain struct {
+	links   []link
+	missing []string
+}
+
+// link 限流链中的一个环节
+type link struct {
+	policy  string          // 限流策略名称
+	group   string          // 所属端点组, 为空表示直接声明的策略
+	limiter web.RateLimiter // 限流器
+}
+
+// partition 计算限流分区键, 端点组内的路由共享同一分区
+func (l link) partition(key string) string {
+	if l.group == "" {
+		return key
+	}
+	return l.group + ":" + key
+}
+
+// NewChain 根据路由声明的限流策略构建限流链
+// 策略名称可以是限流策略, 也可以是端点组名称; 声明 web.RateLimitDisabled 时返回空链
+func NewChain(router web.Router, policies ...string) *Chain {
+	chain := &Chain{}
+
+	if slices.Contains(policies, web.RateLimitDisabled) {
+		return chain
+	}
+
+	names := make([]string, 0, len(policies)+1)
+	if global := router.GlobalRatelimit(); global != "" {
+		names = append(names, global)
+	}
+	names = append(names, policies...)
+
+	seen := make([]string, 0, len(names))
+	for _, name := range names {
+		if slices.Contains(seen, name) {
+			continue
+		}
+		seen = append(seen, name)
+
+		if members, ok := router.RateLimiterGroup(name); ok {
+			for _, member := range members {
+				chain.add(router, member, name)
+			}
+			continue
+		}
+
+		chain.add(router, name, "")
+	}
+
+	return chain
+}
+
+// add 添加限流环节, 同一限流策略只获取一次许可(如端点组包含全局限流策略)
+func (c *Chain) add(router web.Router, policy, group string) {
+	if slices.Contains(c.missing, policy) || slices.ContainsFunc(c.links, func(l link) bool { return l.policy == policy }) {
+		return
+	}
+
+	limiter, ok := router.RateLimiter(policy)
+	if !ok {
+		c.missing = append(c.missing, policy)
+		return
+	}
+
+	c.links = append(c.links, link{policy: policy, group: group, limiter: limiter})
+}
+
+// Empty 限流链是否为空
+func (c *Chain) Empty() bool {
+	return len(c.links) == 0
+}
+
+// Missing 返回未注册的限流策略名称
+func (c *Chain) Missing() []string {
+	return c.missing
+}
+
+// TryAcquire 依次获取所有限流器的许可
+// 全部成功时返回 Lease, 请求结束后需调用 Lease.Release; 任一失败则回滚并返回拒绝信息
+func (c *Chain) TryAcquire(key string) (*Lease, *Rejection) {
+	lease := &Lease{permits: make([]permit, 0, len(c.links)), acquiredAt: time.Now()}
+
+	for _, l := range c.links {
+		partition := l.partition(key)
+
+		allowed, retryAfter := l.limiter.TryAcquire(partition)
+		if !allowed {
+			lease.refund()
+			return nil, &Rejection{Policy: l.policy, RetryAfter: retryAfter}
+		}
+
+		lease.permits = append(lease.permits, permit{key: partition, limiter: l.limiter})
+	}
+
+	return lease, nil
+}
+
+// Lease 限流链获取到的许可
+type Lease struct {
+	permits    []permit
+	acquiredAt time.Time
+}
+
+// permit 单个限流器的许可
+type permit struct {
+	key     string
+	limiter web.RateLimiter
+}
+
+// Release 请求处理结束后释放许可(用于并发限流), 自适应限流器会同时观测请求耗时
+func (l *Lease) Release() {
+	if l == nil {
+		return
+	}
+
+	latency := time.Since(l.acquiredAt)
+	for i := len(l.permits) - 1; i >= 0; i-- {
+		p := l.permits[i]
+		if observer, ok := p.limiter.(latencyObserver); ok {
+			observer.ReleaseWithLatency(p.key, latency)
+			continue
+		}
+		p.limiter.Release(p.key)
+	}
+	l.permits = nil
+}
+
+// refund 按获取的逆序归还许可, 限流器不支持归还时释放许可
+func (l *Lease) refund() {
+	for i := len(l.permits) - 1; i >= 0; i-- {
+		p := l.permits[i]
+		if refundable, ok := p.limiter.(web.RefundableLimiter); ok {
+			refundable.Refund(p.key)
+			continue
+		}
+		p.limiter.Release(p.key)
+	}
+	l.permits = nil
+}
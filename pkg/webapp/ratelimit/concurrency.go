@@ -61,3 +61,13 @@ func (l *ConcurrencyLimiter) Release(key string) {
 		}
 	}
 }
+
+// Refund 归还已获取的并发名额
+func (l *ConcurrencyLimiter) Refund(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.counters[key] > 0 {
+		l.counters[key]--
+	}
+}
@@ -68,3 +68,13 @@ func (l *FixedWindowLimiter) TryAcquire(key string) (bool, time.Duration) {
 	window.count++
 	return true, 0
 }
+
+// Refund 归还当前窗口内已获取的许可
+func (l *FixedWindowLimiter) Refund(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if window, exists := l.windows[key]; exists && window.count > 0 {
+		window.count--
+	}
+}
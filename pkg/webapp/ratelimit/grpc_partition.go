@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// GrpcPartition gRPC 限流分区函数, 返回请求所属的限流分区键
+type GrpcPartition func(ctx context.Context) string
+
+// PartitionByPeer 按客户端地址分区
+func PartitionByPeer() GrpcPartition {
+	return func(ctx context.Context) string {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return ""
+		}
+
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return host
+		}
+		return addr
+	}
+}
+
+// PartitionByMetadata 按请求元数据分区, 元数据不存在时回退到客户端地址
+func PartitionByMetadata(key string) GrpcPartition {
+	fallback := PartitionByPeer()
+
+	return func(ctx context.Context) string {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(key); len(values) > 0 && values[0] != "" {
+				return key + ":" + values[0]
+			}
+		}
+		return fallback(ctx)
+	}
+}
+
+// PartitionByPrincipal 按认证主体分区, 未认证时回退到客户端地址
+func PartitionByPrincipal() GrpcPartition {
+	fallback := PartitionByPeer()
+
+	return func(ctx context.Context) string {
+		if principal, ok := web.ClaimsPrincipalFromContext(ctx); ok && principal.Subject != "" {
+			return "sub:" + principal.Subject
+		}
+		return fallback(ctx)
+	}
+}
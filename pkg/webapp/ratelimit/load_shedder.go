@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Priority 请求优先级
+type Priority int
+
+const (
+	// PriorityLow 低优先级, 最先被拒绝
+	PriorityLow Priority = iota
+	// PriorityNormal 普通优先级
+	PriorityNormal
+	// PriorityHigh 高优先级
+	PriorityHigh
+	// PriorityCritical 关键请求, 只有服务完全饱和时才被拒绝
+	PriorityCritical
+)
+
+// String 优先级名称
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// PriorityPolicy 返回负载削减器指定优先级的策略名称, 如 shedder:low
+func PriorityPolicy(name string, priority Priority) string {
+	return name + ":" + priority.String()
+}
+
+// LoadShedderOptions 负载削减选项
+type LoadShedderOptions struct {
+	MaxInFlight    int                  // 服务最大并发数, 达到该值视为饱和
+	AdaptivePolicy string               // 自适应并发限流策略名称, 设置后以其当前限值作为最大并发数
+	Thresholds     map[Priority]float64 // 各优先级可使用的并发比例
+	RetryAfter     time.Duration        // 被拒绝时建议的重试等待时间
+}
+
+// LoadShedder 优先级负载削减器
+// 所有优先级共享并发计数, 负载升高时低优先级请求先达到可用比例而被拒绝
+type LoadShedder struct {
+	mu       sync.Mutex
+	inflight int
+	capacity func() int
+	options  *LoadShedderOptions
+}
+
+func NewLoadShedder(options *LoadShedderOptions, capacity func() int) *LoadShedder {
+	if capacity == nil {
+		capacity = func() int { return options.MaxInFlight }
+	}
+
+	return &LoadShedder{
+		capacity: capacity,
+		options:  options,
+	}
+}
+
+// For 返回指定优先级的限流器
+func (s *LoadShedder) For(priority Priority) *PriorityLimiter {
+	return &PriorityLimiter{shedder: s, priority: priority}
+}
+
+func (s *LoadShedder) tryAcquire(priority Priority) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	threshold, ok := s.options.Thresholds[priority]
+	if !ok {
+		threshold = 1
+	}
+
+	if float64(s.inflight) >= float64(s.capacity())*threshold {
+		return false, s.options.RetryAfter
+	}
+
+	s.inflight++
+	return true, 0
+}
+
+func (s *LoadShedder) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inflight > 0 {
+		s.inflight--
+	}
+}
+
+// PriorityLimiter 负载削减器在某一优先级下的限流视图
+type PriorityLimiter struct {
+	shedder  *LoadShedder
+	priority Priority
+}
+
+func (l *PriorityLimiter) TryAcquire(key string) (bool, time.Duration) {
+	return l.shedder.tryAcquire(l.priority)
+}
+
+func (l *PriorityLimiter) Release(key string) {
+	l.shedder.release()
+}
+
+func (l *PriorityLimiter) Refund(key string) {
+	l.shedder.release()
+}
@@ -1,6 +1,8 @@
 package ratelimit
 
 import (
+	"time"
+
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 )
 
@@ -8,13 +10,17 @@ import (
 type Options struct {
 	DefaultPolicy string                     // 默认限流策略名称
 	policies      map[string]web.RateLimiter // 限流策略配置
-
+	groups        map[string][]string        // 端点组配置 (组名 -> 限流策略)
+	GrpcPartition GrpcPartition              // gRPC 限流分区方式, 默认按客户端地址
+	grpcMethods   []web.GrpcMethodRule       // gRPC 方法限流配置
 }
 
 func NewOptions() *Options {
 
 	opts := &Options{
-		policies: make(map[string]web.RateLimiter),
+		policies:      make(map[string]web.RateLimiter),
+		groups:        make(map[string][]string),
+		GrpcPartition: PartitionByPeer(),
 	}
 
 	return opts
@@ -56,6 +62,100 @@ func (opt *Options) AddConcurrencyLimiter(name string, configure func(*Concurren
 	opt.policies[name] = NewConcurrencyLimiter(options)
 }
 
+// AddAdaptiveConcurrencyLimiter 添加自适应并发限流器
+func (opt *Options) AddAdaptiveConcurrencyLimiter(name string, configure func(*AdaptiveConcurrencyOptions)) {
+	options := &AdaptiveConcurrencyOptions{
+		Algorithm:        Gradient,
+		InitialLimit:     20,
+		MinLimit:         1,
+		MaxLimit:         1000,
+		LatencyThreshold: time.Second,
+		BackoffRatio:     0.9,
+		Tolerance:        1.5,
+		Smoothing:        0.2,
+		LongWindow:       600,
+	}
+	configure(options)
+
+	if options.MinLimit < 1 || options.MaxLimit < options.MinLimit {
+		panic("invalid adaptive concurrency limits for policy " + name)
+	}
+	if options.LongWindow < 1 {
+		panic("invalid adaptive concurrency long window for policy " + name)
+	}
+	// 初始限值超出范围时取最近的边界
+	options.InitialLimit = min(max(options.InitialLimit, options.MinLimit), options.MaxLimit)
+	opt.policies[name] = NewAdaptiveConcurrencyLimiter(options)
+}
+
+// AddLoadShedder 添加优先级负载削减器
+// 除 name 本身(普通优先级)外, 还会注册各优先级策略, 名称由 PriorityPolicy 生成, 如 name:low
+func (opt *Options) AddLoadShedder(name string, configure func(*LoadShedderOptions)) {
+	options := &LoadShedderOptions{
+		Thresholds: map[Priority]float64{
+			PriorityLow:      0.5,
+			PriorityNormal:   0.8,
+			PriorityHigh:     0.95,
+			PriorityCritical: 1,
+		},
+		RetryAfter: time.Second,
+	}
+	configure(options)
+
+	var capacity func() int
+	if options.AdaptivePolicy != "" {
+		adaptive, ok := opt.policies[options.AdaptivePolicy].(*AdaptiveConcurrencyLimiter)
+		if !ok {
+			panic("adaptive concurrency policy with name " + options.AdaptivePolicy + " does not exist")
+		}
+		capacity = adaptive.Limit
+	} else if options.MaxInFlight <= 0 {
+		panic("load shedder " + name + " requires MaxInFlight or AdaptivePolicy")
+	}
+
+	shedder := NewLoadShedder(options, capacity)
+
+	opt.policies[name] = shedder.For(PriorityNormal)
+	for _, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical} {
+		opt.policies[PriorityPolicy(name, p)] = shedder.For(p)
+	}
+}
+
+// AddEndpointGroup 添加端点组, 引用该组的路由共享组内限流器的计数
+func (opt *Options) AddEndpointGroup(name string, policies ...string) {
+	if _, exists := opt.groups[name]; exists {
+		panic("endpoint group with name " + name + " already exists")
+	}
+	if _, exists := opt.policies[name]; exists {
+		panic("endpoint group name " + name + " conflicts with policy")
+	}
+
+	for _, n := range policies {
+		if _, exists := opt.policies[n]; !exists {
+			panic("policy with name " + n + " does not exist")
+		}
+	}
+
+	opt.groups[name] = policies
+}
+
+// Groups 返回所有端点组
+func (opt *Options) Groups() map[string][]string {
+	return opt.groups
+}
+
+// MapGrpcMethod 为匹配的 gRPC 方法配置限流策略
+// pattern 为完整方法名, 支持通配符, 如 /hello.Greeter/SayHello、/hello.Greeter/*
+func (opt *Options) MapGrpcMethod(pattern string, policies ...string) *Options {
+	opt.grpcMethods = append(opt.grpcMethods, web.NewGrpcMethodRule(pattern, policies...))
+	return opt
+}
+
+// GrpcMethods 返回所有 gRPC 方法限流配置
+func (opt *Options) GrpcMethods() []web.GrpcMethodRule {
+	return opt.grpcMethods
+}
+
 func (opt *Options) Policies(policyName ...string) map[string]web.RateLimiter {
 	if len(policyName) == 0 {
 		return opt.policies
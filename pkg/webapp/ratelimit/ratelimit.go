@@ -17,6 +17,10 @@ const (
 	TokenBucket RateLimitPolicy = "token"
 	// Concurrency 并发数
 	Concurrency RateLimitPolicy = "concurrent"
+	// AdaptiveConcurrency 自适应并发数
+	AdaptiveConcurrency RateLimitPolicy = "adaptive"
+	// LoadShedding 优先级负载削减
+	LoadShedding RateLimitPolicy = "shedding"
 )
 
 // QueueProcessingOrder 队列处理顺序
@@ -103,3 +103,17 @@ func (l *SlidingWindowLimiter) TryAcquire(key string) (bool, time.Duration) {
 
 	return true, 0
 }
+
+// Refund 归还最近一个时间片内已获取的许可
+func (l *SlidingWindowLimiter) Refund(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	segments := l.segments[key]
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i].count > 0 {
+			segments[i].count--
+			return
+		}
+	}
+}
@@ -103,3 +103,13 @@ func (l *TokenBucketLimiter) autoRefill() {
 		l.mu.Unlock()
 	}
 }
+
+// Refund 归还已消耗的令牌
+func (l *TokenBucketLimiter) Refund(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket, exists := l.buckets[key]; exists {
+		bucket.tokens = math.Min(float64(l.options.TokenLimit), bucket.tokens+1)
+	}
+}
@@ -6,24 +6,34 @@ import (
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp/br"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp/deflate"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp/gzip"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp/snappy"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp/zstd"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 )
 
 // Options 管理所有请求解压提供者
 type Options struct {
-	providers map[string]web.ReqDecompression
+	MaxDecompressedSize int64   // 解压后请求体最大字节数, 超过返回 413, 0 表示不限制
+	MaxRatio            float64 // 最大压缩比(解压后/解压前), 超过返回 413, 0 表示不限制
+	providers           map[string]web.ReqDecompression
+	unlimitedRoutes     map[string]struct{}
 }
 
 // NewOptions 初始化 Options 并注册默认 provider
 func NewOptions() *Options {
 	opts := &Options{
-		providers: make(map[string]web.ReqDecompression),
+		MaxDecompressedSize: 30 << 20,
+		MaxRatio:            100,
+		providers:           make(map[string]web.ReqDecompression),
+		unlimitedRoutes:     make(map[string]struct{}),
 	}
 
 	// 注册默认提供者
 	opts.DecompressionProvider("br", br.New())
 	opts.DecompressionProvider("deflate", deflate.New())
 	opts.DecompressionProvider("gzip", gzip.New())
+	opts.DecompressionProvider("zstd", zstd.New())
+	opts.DecompressionProvider("snappy", snappy.New())
 
 	return opts
 }
@@ -37,6 +47,15 @@ func (opts *Options) DecompressionProvider(name string, provider web.ReqDecompre
 	opts.providers[name] = provider
 }
 
+// DisableLimits 指定路由不受解压大小与压缩比限制, 请求体以流的形式解压, 适用于大文件上传
+// paths 为注册路由时的路径模板, 如 /upload/:id
+func (opts *Options) DisableLimits(paths ...string) *Options {
+	for _, path := range paths {
+		opts.unlimitedRoutes[path] = struct{}{}
+	}
+	return opts
+}
+
 func (opts *Options) Decompressions() map[string]web.ReqDecompression {
 
 	return opts.providers
@@ -1,15 +1,32 @@
 package reqdecp
 
-import "github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+import (
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp/zstd"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
 
 type ReqDecompressor struct {
-	reqDecompression map[string]web.ReqDecompression
+	reqDecompression    map[string]web.ReqDecompression
+	unlimitedRoutes     map[string]struct{}
+	maxDecompressedSize int64
+	maxRatio            float64
 }
 
-func NewReqDecompressor(reqDecompressor map[string]web.ReqDecompression) *ReqDecompressor {
+func NewReqDecompressor(opts *Options) *ReqDecompressor {
+	// zstd 解码器的内存上限取自解压大小限制, 避免解压前按帧头声明的大小分配内存
+	if opts.MaxDecompressedSize > 0 {
+		for _, provider := range opts.providers {
+			if z, ok := provider.(*zstd.Zstd); ok && z.MaxMemory == 0 {
+				z.MaxMemory = uint64(opts.MaxDecompressedSize)
+			}
+		}
+	}
 
 	return &ReqDecompressor{
-		reqDecompression: reqDecompressor,
+		reqDecompression:    opts.providers,
+		unlimitedRoutes:     opts.unlimitedRoutes,
+		maxDecompressedSize: opts.MaxDecompressedSize,
+		maxRatio:            opts.MaxRatio,
 	}
 
 }
@@ -25,3 +42,16 @@ func (rd *ReqDecompressor) Decompression(tName string) (web.ReqDecompression, bo
 	return nil, false
 
 }
+
+func (rd *ReqDecompressor) MaxDecompressedSize() int64 {
+	return rd.maxDecompressedSize
+}
+
+func (rd *ReqDecompressor) MaxRatio() float64 {
+	return rd.maxRatio
+}
+
+func (rd *ReqDecompressor) LimitsDisabled(path string) bool {
+	_, ok := rd.unlimitedRoutes[path]
+	return ok
+}
@@ -0,0 +1,24 @@
+package snappy
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+)
+
+// Snappy snappy 流式分帧格式
+type Snappy struct {
+}
+
+func New() *Snappy {
+	return &Snappy{}
+}
+
+func (b *Snappy) Type() string {
+	return "snappy"
+}
+
+func (b *Snappy) Decompression(r io.Reader) (io.ReadCloser, error) {
+
+	return io.NopCloser(snappy.NewReader(r)), nil
+}
@@ -0,0 +1,41 @@
+package zstd
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultMaxWindow 默认允许的最大窗口
+// 解码器读取帧头后即按窗口大小分配历史缓冲区, 需在解压大小与压缩比校验之前拒绝声明超大窗口的请求
+const DefaultMaxWindow = 8 << 20
+
+type Zstd struct {
+	MaxWindow uint64 // 允许的最大窗口, 超过时拒绝解压
+	MaxMemory uint64 // 解码允许占用的最大内存, 0 表示仅受 MaxWindow 限制
+}
+
+func New() *Zstd {
+	return &Zstd{MaxWindow: DefaultMaxWindow}
+}
+
+func (b *Zstd) Type() string {
+	return "zstd"
+}
+
+func (b *Zstd) Decompression(r io.Reader) (io.ReadCloser, error) {
+	options := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+	if b.MaxWindow > 0 {
+		options = append(options, zstd.WithDecoderMaxWindow(b.MaxWindow))
+	}
+	if b.MaxMemory > 0 {
+		options = append(options, zstd.WithDecoderMaxMemory(b.MaxMemory))
+	}
+
+	decoder, err := zstd.NewReader(r, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.IOReadCloser(), nil
+}
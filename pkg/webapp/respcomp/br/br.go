@@ -0,0 +1,61 @@
+package br
+
+import (
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type Brotli struct {
+	pools sync.Map // 按压缩级别复用编码器
+}
+
+func New() *Brotli {
+	return &Brotli{}
+}
+
+func (b *Brotli) Type() string {
+	return "br"
+}
+
+// Compression 创建压缩流, 编码器按级别复用
+// CompressionOptimal 使用最高级别 11, 压缩速度慢且占用内存多, 仅适用于静态或可缓存的响应
+func (b *Brotli) Compression(w io.Writer, level web.CompressionLevel) (io.WriteCloser, error) {
+	pool := b.pool(level)
+	if writer, ok := pool.Get().(*brotli.Writer); ok {
+		writer.Reset(w)
+		return &pooledWriter{Writer: writer, pool: pool}, nil
+	}
+
+	var writer *brotli.Writer
+	switch level {
+	case web.CompressionFastest:
+		writer = brotli.NewWriterLevel(w, brotli.BestSpeed)
+	case web.CompressionOptimal:
+		writer = brotli.NewWriterLevel(w, brotli.BestCompression)
+	default:
+		// brotli 默认级别 11 对动态响应过慢, 使用 4
+		writer = brotli.NewWriterLevel(w, 4)
+	}
+	return &pooledWriter{Writer: writer, pool: pool}, nil
+}
+
+func (b *Brotli) pool(level web.CompressionLevel) *sync.Pool {
+	pool, _ := b.pools.LoadOrStore(level, &sync.Pool{})
+	return pool.(*sync.Pool)
+}
+
+// pooledWriter 关闭后将编码器放回池中
+type pooledWriter struct {
+	*brotli.Writer
+	pool *sync.Pool
+}
+
+func (w *pooledWriter) Close() error {
+	err := w.Writer.Close()
+	w.Writer.Reset(nil)
+	w.pool.Put(w.Writer)
+	return err
+}
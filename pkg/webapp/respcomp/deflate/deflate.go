@@ -0,0 +1,32 @@
+package deflate
+
+import (
+	"compress/zlib"
+	"io"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// Deflate HTTP deflate 编码, 即 zlib 格式(RFC 9110)
+type Deflate struct {
+}
+
+func New() *Deflate {
+	return &Deflate{}
+}
+
+func (b *Deflate) Type() string {
+	return "deflate"
+}
+
+func (b *Deflate) Compression(w io.Writer, level web.CompressionLevel) (io.WriteCloser, error) {
+
+	switch level {
+	case web.CompressionFastest:
+		return zlib.NewWriterLevel(w, zlib.BestSpeed)
+	case web.CompressionOptimal:
+		return zlib.NewWriterLevel(w, zlib.BestCompression)
+	default:
+		return zlib.NewWriterLevel(w, zlib.DefaultCompression)
+	}
+}
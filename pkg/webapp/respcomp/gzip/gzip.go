@@ -0,0 +1,31 @@
+package gzip
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type Gzip struct {
+}
+
+func New() *Gzip {
+	return &Gzip{}
+}
+
+func (b *Gzip) Type() string {
+	return "gzip"
+}
+
+func (b *Gzip) Compression(w io.Writer, level web.CompressionLevel) (io.WriteCloser, error) {
+
+	switch level {
+	case web.CompressionFastest:
+		return gzip.NewWriterLevel(w, gzip.BestSpeed)
+	case web.CompressionOptimal:
+		return gzip.NewWriterLevel(w, gzip.BestCompression)
+	default:
+		return gzip.NewWriterLevel(w, gzip.DefaultCompression)
+	}
+}
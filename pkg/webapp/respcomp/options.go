@@ -0,0 +1,75 @@
+package respcomp
+
+import (
+	"fmt"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/respcomp/br"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/respcomp/deflate"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/respcomp/gzip"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/respcomp/zstd"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// DefaultMimeTypes 默认允许压缩的响应类型
+var DefaultMimeTypes = []string{
+	"text/plain",
+	"text/css",
+	"text/html",
+	"text/xml",
+	"text/javascript",
+	"text/event-stream",
+	"application/javascript",
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// Options 管理所有响应压缩提供者
+type Options struct {
+	MinSize   int                  // 响应体达到该字节数才压缩, 流式响应提前刷新时不受限制
+	Level     web.CompressionLevel // 压缩级别
+	MimeTypes []string             // 允许压缩的响应类型, 支持 text/* 形式的通配
+	providers map[string]web.RespCompression
+	order     []string // 提供者优先级, 客户端权重相同时按注册顺序选择
+}
+
+// NewOptions 初始化 Options 并注册默认 provider
+func NewOptions() *Options {
+	opts := &Options{
+		MinSize:   1024,
+		Level:     web.CompressionDefault,
+		MimeTypes: append([]string(nil), DefaultMimeTypes...),
+		providers: make(map[string]web.RespCompression),
+	}
+
+	// 注册默认提供者
+	opts.CompressionProvider("br", br.New())
+	opts.CompressionProvider("zstd", zstd.New())
+	opts.CompressionProvider("gzip", gzip.New())
+	opts.CompressionProvider("deflate", deflate.New())
+
+	return opts
+}
+
+// CompressionProvider 注册一个压缩提供者，如果已存在则 panic
+func (opts *Options) CompressionProvider(name string, provider web.RespCompression) {
+	if _, exists := opts.providers[name]; exists {
+		panic(fmt.Errorf("provider 已存在: %s", name))
+	}
+
+	opts.providers[name] = provider
+	opts.order = append(opts.order, name)
+}
+
+// AddMimeTypes 添加允许压缩的响应类型
+func (opts *Options) AddMimeTypes(mimeTypes ...string) *Options {
+	opts.MimeTypes = append(opts.MimeTypes, mimeTypes...)
+	return opts
+}
+
+func (opts *Options) Compressions() map[string]web.RespCompression {
+
+	return opts.providers
+}
@@ -0,0 +1,119 @@
+package respcomp
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+type RespCompressor struct {
+	providers map[string]web.RespCompression
+	order     []string
+	mimeTypes map[string]struct{}
+	wildcards []string
+	minSize   int
+	level     web.CompressionLevel
+}
+
+func NewRespCompressor(opts *Options) *RespCompressor {
+
+	rc := &RespCompressor{
+		providers: opts.providers,
+		order:     opts.order,
+		mimeTypes: make(map[string]struct{}, len(opts.MimeTypes)),
+		minSize:   opts.MinSize,
+		level:     opts.Level,
+	}
+
+	for _, mimeType := range opts.MimeTypes {
+		mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+		if prefix, ok := strings.CutSuffix(mimeType, "/*"); ok {
+			rc.wildcards = append(rc.wildcards, prefix+"/")
+			continue
+		}
+		rc.mimeTypes[mimeType] = struct{}{}
+	}
+
+	return rc
+}
+
+// Negotiate 根据 Accept-Encoding 选择权重最高的提供者, 权重相同时按注册顺序
+func (rc *RespCompressor) Negotiate(acceptEncoding string) (web.RespCompression, bool) {
+	if acceptEncoding == "" {
+		return nil, false
+	}
+
+	weights := make(map[string]float64)
+	wildcard := -1.0
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		weights[name] = q
+	}
+
+	var (
+		best       web.RespCompression
+		bestWeight float64
+	)
+
+	for _, name := range rc.order {
+		weight, ok := weights[name]
+		if !ok {
+			weight = wildcard
+		}
+
+		if weight > bestWeight {
+			best, bestWeight = rc.providers[name], weight
+		}
+	}
+
+	return best, best != nil
+}
+
+// Compressible 响应类型是否允许压缩
+func (rc *RespCompressor) Compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if _, ok := rc.mimeTypes[mediaType]; ok {
+		return true
+	}
+
+	for _, prefix := range rc.wildcards {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rc *RespCompressor) MinSize() int {
+	return rc.minSize
+}
+
+func (rc *RespCompressor) Level() web.CompressionLevel {
+	return rc.level
+}
@@ -0,0 +1,70 @@
+package zstd
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// windowSize 编码窗口大小, 默认 8MB 窗口会为每个响应分配约 16MB 历史缓冲区
+const windowSize = 1 << 20
+
+type Zstd struct {
+	pools sync.Map // 按压缩级别复用编码器
+}
+
+func New() *Zstd {
+	return &Zstd{}
+}
+
+func (b *Zstd) Type() string {
+	return "zstd"
+}
+
+func (b *Zstd) Compression(w io.Writer, level web.CompressionLevel) (io.WriteCloser, error) {
+	pool := b.pool(level)
+	if encoder, ok := pool.Get().(*zstd.Encoder); ok {
+		encoder.Reset(w)
+		return &pooledEncoder{Encoder: encoder, pool: pool}, nil
+	}
+
+	encoderLevel := zstd.SpeedDefault
+	switch level {
+	case web.CompressionFastest:
+		encoderLevel = zstd.SpeedFastest
+	case web.CompressionOptimal:
+		encoderLevel = zstd.SpeedBestCompression
+	}
+
+	// 单并发避免为每个响应启动多个协程, 限制窗口并降低编码器内存占用
+	encoder, err := zstd.NewWriter(w,
+		zstd.WithEncoderLevel(encoderLevel),
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithWindowSize(windowSize),
+		zstd.WithLowerEncoderMem(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledEncoder{Encoder: encoder, pool: pool}, nil
+}
+
+func (b *Zstd) pool(level web.CompressionLevel) *sync.Pool {
+	pool, _ := b.pools.LoadOrStore(level, &sync.Pool{})
+	return pool.(*sync.Pool)
+}
+
+// pooledEncoder 关闭后将编码器放回池中
+type pooledEncoder struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (e *pooledEncoder) Close() error {
+	err := e.Encoder.Close()
+	e.Encoder.Reset(nil)
+	e.pool.Put(e.Encoder)
+	return err
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"context"
+
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/authz"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
@@ -12,6 +14,12 @@ type Router struct {
 	authenticate    map[string]web.Authenticate                       // 鉴权handler
 	authorize       map[string]func(claims *web.ClaimsPrincipal) bool // 授权handler
 	rateLimiters    map[string]web.RateLimiter                        // 限流 handler 注册表 (name -> handler)
+	rateLimitGroups map[string][]string                               // 限流端点组 (group -> policies)
+	grpcSchemes     []web.GrpcMethodRule                              // gRPC 方法鉴权方案
+	grpcAnonymous   []web.GrpcMethodRule                              // gRPC 允许匿名访问的方法
+	grpcPolicies    []web.GrpcMethodRule                              // gRPC 方法授权策略
+	grpcRateLimits  []web.GrpcMethodRule                              // gRPC 方法限流配置
+	grpcPartition   ratelimit.GrpcPartition                           // gRPC 限流分区
 	globalScheme    string                                            // 默认鉴权方案默认鉴权方案
 	globalPolicy    string                                            // 默认鉴权方案默认鉴权方案
 	globalRatelimit string                                            // 默认鉴权方案默认鉴权方案
@@ -22,6 +30,12 @@ func NewRouter(authOpts *auth.Options, authzOpts *authz.Options, ratelimitOpts *
 		authenticate:    authOpts.Schemes(),
 		authorize:       authzOpts.Policies(),
 		rateLimiters:    ratelimitOpts.Policies(),
+		rateLimitGroups: ratelimitOpts.Groups(),
+		grpcSchemes:     authOpts.GrpcMethods(),
+		grpcAnonymous:   authOpts.GrpcAnonymousMethods(),
+		grpcPolicies:    authzOpts.GrpcMethods(),
+		grpcRateLimits:  ratelimitOpts.GrpcMethods(),
+		grpcPartition:   ratelimitOpts.GrpcPartition,
 		globalScheme:    authOpts.DefaultScheme,
 		globalPolicy:    authzOpts.DefaultPolicy,
 		globalRatelimit: ratelimitOpts.DefaultPolicy,
@@ -70,3 +84,54 @@ func (p *Router) RateLimiter(policy string) (web.RateLimiter, bool) {
 
 	return nil, false
 }
+
+// RateLimiterGroup 限流端点组
+func (p *Router) RateLimiterGroup(group string) ([]string, bool) {
+	if policies, ok := p.rateLimitGroups[group]; ok {
+		return policies, true
+	}
+
+	return nil, false
+}
+
+// GrpcSchemes gRPC 方法鉴权方案, 返回所有匹配规则的鉴权方案
+func (p *Router) GrpcSchemes(fullMethod string) []string {
+	schemes, _ := web.MatchGrpcMethod(p.grpcSchemes, fullMethod)
+	return schemes
+}
+
+// GrpcAllowAnonymous gRPC 方法是否允许匿名访问
+func (p *Router) GrpcAllowAnonymous(fullMethod string) bool {
+	_, matched := web.MatchGrpcMethod(p.grpcAnonymous, fullMethod)
+	return matched
+}
+
+// GrpcPolicies gRPC 方法授权策略, 返回所有匹配规则的授权策略
+func (p *Router) GrpcPolicies(fullMethod string) []string {
+	policies, _ := web.MatchGrpcMethod(p.grpcPolicies, fullMethod)
+	return policies
+}
+
+// GrpcRateLimit gRPC 方法限流方案, 返回所有匹配规则的限流策略
+func (p *Router) GrpcRateLimit(fullMethod string) []string {
+	policies, _ := web.MatchGrpcMethod(p.grpcRateLimits, fullMethod)
+	return policies
+}
+
+// GrpcMethodRules 配置的全部 gRPC 方法规则
+func (p *Router) GrpcMethodRules() web.GrpcMethodRules {
+	return web.GrpcMethodRules{
+		Schemes:    p.grpcSchemes,
+		Policies:   p.grpcPolicies,
+		RateLimits: p.grpcRateLimits,
+	}
+}
+
+// GrpcPartitionKey gRPC 限流分区键
+func (p *Router) GrpcPartitionKey(ctx context.Context) string {
+	if p.grpcPartition == nil {
+		return ratelimit.PartitionByPeer()(ctx)
+	}
+
+	return p.grpcPartition(ctx)
+}
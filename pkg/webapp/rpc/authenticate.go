@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticate gRPC 鉴权拦截器
+type Authenticate struct {
+	web.Router
+	logger *zap.Logger
+}
+
+// NewAuthenticate 初始化 gRPC 鉴权拦截器
+func NewAuthenticate(router web.Router, logger *zap.Logger) *Authenticate {
+	return &Authenticate{
+		Router: router,
+		logger: logger,
+	}
+}
+
+// Unary 一元调用鉴权
+func (a *Authenticate) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream 流式调用鉴权
+func (a *Authenticate) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate 依次尝试方法配置的鉴权方案, 成功后将 ClaimsPrincipal 存入 context
+func (a *Authenticate) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	// 跳过允许匿名访问的方法
+	if a.GrpcAllowAnonymous(fullMethod) {
+		return ctx, nil
+	}
+
+	schemes := a.GrpcSchemes(fullMethod)
+	if len(schemes) == 0 {
+		if defaultScheme := a.GlobalScheme(); defaultScheme != "" {
+			schemes = append(schemes, defaultScheme)
+		} else {
+			a.logger.Error("grpc method not configured with scheme", zap.String("method", fullMethod))
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+	}
+
+	req := newHttpRequest(ctx, fullMethod)
+
+	for _, scheme := range schemes {
+		handler, ok := a.Authenticate(scheme)
+		if !ok {
+			a.logger.Warn("authentication scheme not found",
+				zap.String("method", fullMethod),
+				zap.String("scheme", scheme))
+			continue
+		}
+
+		claims, err := handler.Authenticate(req)
+		if err == nil && claims != nil {
+			return web.WithClaimsPrincipal(ctx, claims), nil
+		}
+
+		a.logger.Error("authentication failed",
+			zap.String("method", fullMethod),
+			zap.String("scheme", scheme),
+			zap.Error(err))
+	}
+
+	// 所有 scheme 都认证失败
+	return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+}
+
+// newHttpRequest 将 gRPC 请求元数据适配为 *http.Request, 供 web.Authenticate 使用
+func newHttpRequest(ctx context.Context, fullMethod string) *http.Request {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullMethod, http.NoBody)
+	if err != nil {
+		req = (&http.Request{Method: http.MethodPost, Header: make(http.Header)}).WithContext(ctx)
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			// 跳过 :authority 等伪首部
+			if strings.HasPrefix(key, ":") {
+				continue
+			}
+			for _, v := range values {
+				req.Header.Add(key, v)
+			}
+		}
+
+		if authority := md.Get(":authority"); len(authority) > 0 {
+			req.Host = authority[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	return req
+}
+
+// serverStream 替换 context 的 grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
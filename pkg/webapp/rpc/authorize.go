@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Authorize gRPC 授权拦截器
+type Authorize struct {
+	web.Router
+	logger *zap.Logger
+}
+
+// NewAuthorize 初始化 gRPC 授权拦截器
+func NewAuthorize(router web.Router, logger *zap.Logger) *Authorize {
+	return &Authorize{
+		Router: router,
+		logger: logger,
+	}
+}
+
+// Unary 一元调用授权
+func (a *Authorize) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream 流式调用授权
+func (a *Authorize) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// authorize 校验方法配置的授权策略, 未配置时使用默认授权策略
+func (a *Authorize) authorize(ctx context.Context, fullMethod string) error {
+	if a.GrpcAllowAnonymous(fullMethod) {
+		return nil
+	}
+
+	claims, ok := web.ClaimsPrincipalFromContext(ctx)
+	if !ok {
+		a.logger.Error("authorization failed: ClaimsPrincipal is nil", zap.String("method", fullMethod))
+		return status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+
+	policyNames := a.GrpcPolicies(fullMethod)
+	if len(policyNames) == 0 && a.GlobalPolicy() != "" {
+		policyNames = append(policyNames, a.GlobalPolicy())
+	}
+
+	for _, policyName := range policyNames {
+		// 未注册的策略视为授权失败, 避免误配置时跳过授权
+		policyFunc, ok := a.Authorize(policyName)
+		if !ok {
+			a.logger.Error("authorization failed: policy not found",
+				zap.String("method", fullMethod),
+				zap.String("policy", policyName))
+			return status.Error(codes.PermissionDenied, "permission denied")
+		}
+
+		if !policyFunc(claims) {
+			a.logger.Warn("authorization failed",
+				zap.String("method", fullMethod),
+				zap.String("policy", policyName))
+			return status.Error(codes.PermissionDenied, "permission denied")
+		}
+	}
+
+	return nil
+}
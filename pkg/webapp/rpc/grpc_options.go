@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"reflect"
+
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// GrpcOptions gRPC 服务配置选项
+type GrpcOptions struct {
+	MaxRecvMsgSize       int                          // 最大接收消息大小(字节), 0 表示使用 gRPC 默认值 4MB
+	MaxSendMsgSize       int                          // 最大发送消息大小(字节), 0 表示使用 gRPC 默认值
+	MaxConcurrentStreams uint32                       // 单连接最大并发流, 0 表示不限制
+	Keepalive            *keepalive.ServerParameters  // 服务端 keepalive 参数
+	KeepaliveEnforcement *keepalive.EnforcementPolicy // 客户端 keepalive 约束策略
+	EnableReflection     bool                         // 是否注册 gRPC 反射服务, 会公开全部服务定义, 默认关闭
+	EnableHealthCheck    bool                         // 是否注册 grpc.health.v1 健康检查服务
+	Transcoding          *TranscodingOptions          // HTTP/JSON 转码选项, 为 nil 时不暴露 HTTP 路由
+	serverOptions        []grpc.ServerOption          // 自定义 server 选项
+	interceptors         []any                        // 拦截器构造函数
+}
+
+// NewGrpcOptions 创建 gRPC 服务配置选项, 默认注册健康检查服务
+func NewGrpcOptions() *GrpcOptions {
+	return &GrpcOptions{
+		EnableHealthCheck: true,
+	}
+}
+
+// AddServerOption 添加自定义 server 选项
+func (o *GrpcOptions) AddServerOption(opts ...grpc.ServerOption) *GrpcOptions {
+	o.serverOptions = append(o.serverOptions, opts...)
+	return o
+}
+
+// EnableTranscoding 将 MapGrpcServices 注册的服务以 HTTP/JSON 形式暴露在 web 路由上
+// 转码请求与原生 gRPC 调用经过同一条拦截器链(鉴权、授权、限流等)
+func (o *GrpcOptions) EnableTranscoding(fn ...func(*TranscodingOptions)) *GrpcOptions {
+	if o.Transcoding == nil {
+		o.Transcoding = NewTranscodingOptions()
+	}
+	for _, f := range fn {
+		f(o.Transcoding)
+	}
+	return o
+}
+
+// UseInterceptor 注册拦截器, 构造函数由容器解析, 按注册顺序组成拦截器链
+func (o *GrpcOptions) UseInterceptor(constructors ...any) *GrpcOptions {
+	for _, constructor := range constructors {
+		constructorType := reflect.TypeOf(constructor)
+		if constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 {
+			panic("UseInterceptor: constructor must be a function that returns Interceptor")
+		}
+		o.interceptors = append(o.interceptors, constructor)
+	}
+	return o
+}
+
+// UseLogger 注册 zap 日志拦截器
+func (o *GrpcOptions) UseLogger() *GrpcOptions {
+	return o.UseInterceptor(NewLogger)
+}
+
+// UseRecovery 注册 panic 恢复拦截器
+func (o *GrpcOptions) UseRecovery() *GrpcOptions {
+	return o.UseInterceptor(NewRecovery)
+}
+
+// UseValidator 注册请求校验拦截器
+func (o *GrpcOptions) UseValidator() *GrpcOptions {
+	return o.UseInterceptor(NewValidator)
+}
+
+// ServerOptions 返回构建 gRPC server 的全部选项
+func (o *GrpcOptions) ServerOptions() []grpc.ServerOption {
+	opts := make([]grpc.ServerOption, 0, len(o.serverOptions)+5)
+
+	if o.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(o.MaxRecvMsgSize))
+	}
+	if o.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(o.MaxSendMsgSize))
+	}
+	if o.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(o.MaxConcurrentStreams))
+	}
+	if o.Keepalive != nil {
+		opts = append(opts, grpc.KeepaliveParams(*o.Keepalive))
+	}
+	if o.KeepaliveEnforcement != nil {
+		opts = append(opts, grpc.KeepaliveEnforcementPolicy(*o.KeepaliveEnforcement))
+	}
+
+	return append(opts, o.serverOptions...)
+}
+
+// Container 返回注册到容器的选项
+func (o *GrpcOptions) Container() []fx.Option {
+	container := []fx.Option{
+		fx.Supply(o),
+		fx.Supply(NewInterceptorChain()),
+	}
+
+	for _, constructor := range o.interceptors {
+		container = append(container, ProvideInterceptor(constructor)...)
+	}
+
+	return container
+}
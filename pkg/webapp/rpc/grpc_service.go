@@ -1,12 +1,10 @@
 package rpc
 
 import (
-	"context"
-	"net"
-
-	"go.uber.org/fx"
-	"go.uber.org/zap"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 // GrpcService 定义 gRPC 服务接口
@@ -14,31 +12,19 @@ type GrpcService interface {
 	Register(server *grpc.Server)
 }
 
-// NewGrpcServer 启动 gRPC 服务
-func NewGrpcServer(lc fx.Lifecycle, logger *zap.Logger, port string) *grpc.Server {
-	grpcServer := grpc.NewServer()
+// NewGrpcServer 按配置创建 gRPC server, 并注册反射与健康检查服务
+func NewGrpcServer(opts *GrpcOptions, chain *InterceptorChain, healthCheck web.HealthCheck) *grpc.Server {
+	serverOptions := append(opts.ServerOptions(), chain.ServerOptions()...)
+
+	server := grpc.NewServer(serverOptions...)
+
+	if opts.EnableReflection {
+		reflection.Register(server)
+	}
 
-	// 使用 fx 生命周期启动 gRPC
-	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
-			go func() {
-				listener, err := net.Listen("tcp", ":"+port)
-				if err != nil {
-					logger.Fatal("failed to listen", zap.Error(err))
-				}
-				logger.Info("starting gRPC server", zap.String("GRPC Port", port))
-				if err := grpcServer.Serve(listener); err != nil {
-					logger.Fatal("gRPC Serve failed", zap.Error(err))
-				}
-			}()
-			return nil
-		},
-		OnStop: func(ctx context.Context) error {
-			logger.Info("stopping gRPC server...")
-			grpcServer.GracefulStop()
-			return nil
-		},
-	})
+	if opts.EnableHealthCheck {
+		grpc_health_v1.RegisterHealthServer(server, NewHealthService(healthCheck, server))
+	}
 
-	return grpcServer
+	return server
 }
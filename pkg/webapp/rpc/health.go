@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// watchInterval Watch 轮询健康状态的间隔
+const watchInterval = time.Second
+
+// HealthService grpc.health.v1 健康检查服务, 状态来自健康检查注册表
+// service 为空时返回整体状态; 为健康检查名称时返回该项检查的状态; 为已注册的 gRPC 服务名时返回整体状态
+type HealthService struct {
+	grpc_health_v1.UnimplementedHealthServer
+	healthCheck web.HealthCheck
+	server      *grpc.Server
+}
+
+// NewHealthService 创建健康检查服务
+func NewHealthService(healthCheck web.HealthCheck, server *grpc.Server) *HealthService {
+	return &HealthService{
+		healthCheck: healthCheck,
+		server:      server,
+	}
+}
+
+// Check 查询健康状态
+func (s *HealthService) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	servingStatus, ok := s.status(ctx, req.GetService())
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch 订阅健康状态, 状态变化时推送
+func (s *HealthService) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_ServingStatus(-1)
+
+	for {
+		servingStatus, ok := s.status(stream.Context(), req.GetService())
+		if !ok {
+			servingStatus = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+
+		if servingStatus != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return err
+			}
+			last = servingStatus
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-ticker.C:
+		}
+	}
+}
+
+// status 计算服务状态, 返回 false 表示服务不存在
+func (s *HealthService) status(ctx context.Context, service string) (grpc_health_v1.HealthCheckResponse_ServingStatus, bool) {
+	var report web.HealthReport
+
+	switch {
+	case service == "":
+		report = s.healthCheck.Check(ctx)
+	case s.healthCheck.Has(service):
+		report = s.healthCheck.Check(ctx, service)
+	default:
+		if _, ok := s.server.GetServiceInfo()[service]; !ok {
+			return grpc_health_v1.HealthCheckResponse_UNKNOWN, false
+		}
+		report = s.healthCheck.Check(ctx)
+	}
+
+	if !s.healthCheck.Ready() || report.Status != web.Healthy {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, true
+	}
+
+	return grpc_health_v1.HealthCheckResponse_SERVING, true
+}
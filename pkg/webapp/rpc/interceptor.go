@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+)
+
+// Interceptor gRPC 服务端拦截器接口
+type Interceptor interface {
+	Unary() grpc.UnaryServerInterceptor   // 一元调用拦截器
+	Stream() grpc.StreamServerInterceptor // 流式调用拦截器
+}
+
+// InterceptorChain 拦截器链, 按注册顺序执行
+type InterceptorChain struct {
+	unary  []grpc.UnaryServerInterceptor
+	stream []grpc.StreamServerInterceptor
+}
+
+// NewInterceptorChain 创建拦截器链
+func NewInterceptorChain() *InterceptorChain {
+	return &InterceptorChain{}
+}
+
+// Add 添加拦截器
+func (c *InterceptorChain) Add(interceptor Interceptor) {
+	c.unary = append(c.unary, interceptor.Unary())
+	c.stream = append(c.stream, interceptor.Stream())
+}
+
+// ServerOptions 返回拦截器链对应的 server 选项
+func (c *InterceptorChain) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.unary...),
+		grpc.ChainStreamInterceptor(c.stream...),
+	}
+}
+
+// ProvideInterceptor 返回注册拦截器的容器选项, 拦截器由容器构造后加入拦截器链
+func ProvideInterceptor(constructor any) []fx.Option {
+	constructorType := reflect.TypeOf(constructor)
+	if constructorType.Kind() != reflect.Func || constructorType.NumOut() == 0 {
+		panic("ProvideInterceptor: constructor must be a function that returns Interceptor")
+	}
+
+	return []fx.Option{
+		fx.Provide(constructor),
+		fx.Invoke(makeInterceptorInvoke(constructorType.Out(0))),
+	}
+}
+
+func makeInterceptorInvoke(interceptorType reflect.Type) any {
+	// 构造函数类型：func(<Interceptor>, *InterceptorChain)
+	fnType := reflect.FuncOf(
+		[]reflect.Type{interceptorType, reflect.TypeOf((*InterceptorChain)(nil))},
+		[]reflect.Type{},
+		false,
+	)
+
+	fn := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		interceptor, ok := args[0].Interface().(Interceptor)
+		if !ok {
+			panic(fmt.Sprintf("type %v does not implement Interceptor", args[0].Type()))
+		}
+
+		args[1].Interface().(*InterceptorChain).Add(interceptor)
+
+		return nil
+	})
+
+	return fn.Interface()
+}
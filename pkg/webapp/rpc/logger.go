@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Logger gRPC zap 日志拦截器
+type Logger struct {
+	logger *zap.Logger
+}
+
+// NewLogger 初始化 gRPC 日志拦截器
+func NewLogger(logger *zap.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Unary 记录一元调用日志
+func (l *Logger) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.log(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// Stream 记录流式调用日志
+func (l *Logger) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		l.log(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// log 成功调用记录 debug 日志, 客户端错误记录 warn, 服务端错误记录 error
+func (l *Logger) log(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+
+	fields := []zap.Field{
+		zap.String("code", code.String()),
+		zap.String("method", method),
+		zap.Duration("latency", time.Since(start)),
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+
+	switch code {
+	case codes.OK:
+		l.logger.Debug("GRPC Request", fields...)
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		l.logger.Error("GRPC Request", fields...)
+	default:
+		l.logger.Warn("GRPC Request", fields...)
+	}
+}
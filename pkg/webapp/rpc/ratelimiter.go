@@ -0,0 +1,123 @@
+package rpc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimiter gRPC 限流拦截器
+type RateLimiter struct {
+	web.Router
+	logger *zap.Logger
+}
+
+// NewRateLimiter 初始化 gRPC 限流拦截器
+func NewRateLimiter(router web.Router, logger *zap.Logger) *RateLimiter {
+	return &RateLimiter{
+		Router: router,
+		logger: logger,
+	}
+}
+
+// Unary 一元调用限流
+func (r *RateLimiter) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		lease, retryAfter, err := r.acquire(ctx, info.FullMethod)
+		if err != nil {
+			_ = grpc.SetHeader(ctx, retryAfterHeader(retryAfter))
+			return nil, err
+		}
+		defer lease.Release()
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream 流式调用限流, 许可在整个流结束后释放
+func (r *RateLimiter) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		lease, retryAfter, err := r.acquire(ss.Context(), info.FullMethod)
+		if err != nil {
+			_ = ss.SetHeader(retryAfterHeader(retryAfter))
+			return err
+		}
+		defer lease.Release()
+
+		return handler(srv, ss)
+	}
+}
+
+// acquire 按链式语义获取限流许可
+func (r *RateLimiter) acquire(ctx context.Context, fullMethod string) (*ratelimit.Lease, int, error) {
+	// HTTP/JSON 转码请求已由 HTTP 限流中间件计数
+	if rateLimited(ctx) {
+		return nil, 0, nil
+	}
+
+	key := r.GrpcPartitionKey(ctx)
+
+	chain := ratelimit.NewChain(r.Router, r.GrpcRateLimit(fullMethod)...)
+
+	for _, policy := range chain.Missing() {
+		r.logger.Error("rate limit handler not found",
+			zap.String("method", fullMethod),
+			zap.String("policy", policy),
+			zap.String("key", key))
+	}
+
+	if chain.Empty() {
+		return nil, 0, nil
+	}
+
+	lease, rejection := chain.TryAcquire(key)
+	if rejection == nil {
+		return lease, 0, nil
+	}
+
+	r.logger.Info("rate limit exceeded",
+		zap.String("method", fullMethod),
+		zap.String("policy", rejection.Policy),
+		zap.String("key", key),
+		zap.Duration("retryAfter", rejection.RetryAfter))
+
+	st := status.New(codes.ResourceExhausted, "too many requests")
+	if detailed, err := st.WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(rejection.RetryAfter)},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     rejection.Policy,
+			Description: "rate limit exceeded",
+		}}},
+	); err == nil {
+		st = detailed
+	}
+
+	return nil, int(rejection.RetryAfter.Seconds()), st.Err()
+}
+
+// rateLimitedKey 请求已经过限流的 context 键
+type rateLimitedKey struct{}
+
+// WithRateLimited 标记请求已经过限流, 用于进程内转发的 HTTP/JSON 转码请求
+func WithRateLimited(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateLimitedKey{}, true)
+}
+
+func rateLimited(ctx context.Context) bool {
+	limited, _ := ctx.Value(rateLimitedKey{}).(bool)
+	return limited
+}
+
+// retryAfterHeader 构造 retry-after 响应头(秒)
+func retryAfterHeader(seconds int) metadata.MD {
+	return metadata.Pairs("retry-after", strconv.Itoa(seconds))
+}
@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Recovery gRPC panic 恢复拦截器
+type Recovery struct {
+	logger *zap.Logger
+}
+
+// NewRecovery 初始化 gRPC panic 恢复拦截器
+func NewRecovery(logger *zap.Logger) *Recovery {
+	return &Recovery{logger: logger}
+}
+
+// Unary 捕获一元调用中的 panic, 返回 Internal
+func (r *Recovery) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = r.recovered(info.FullMethod, p)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream 捕获流式调用中的 panic, 返回 Internal
+func (r *Recovery) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = r.recovered(info.FullMethod, p)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func (r *Recovery) recovered(method string, p any) error {
+	r.logger.Error("panic recovered",
+		zap.Any("error", p),
+		zap.String("method", method),
+		zap.ByteString("stack", debug.Stack()),
+	)
+
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,577 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// HttpRule gRPC 方法到 HTTP 路由的映射
+type HttpRule struct {
+	Method       string            // HTTP 方法
+	Path         string            // 路由路径, 路径参数使用 :name 与 *name 形式
+	FullMethod   string            // gRPC 完整方法名, 如 /hello.HelloService/SayHello
+	Body         string            // 请求体映射的字段, * 表示整个请求消息, 为空表示无请求体
+	ResponseBody string            // 响应体映射的字段, 为空表示整个响应消息
+	PathParams   map[string]string // 路径参数名 -> 请求消息字段路径
+	input        protoreflect.MessageType
+	output       protoreflect.MessageType
+}
+
+// defaultMaxRecvMsgSize gRPC 默认的最大接收消息大小
+const defaultMaxRecvMsgSize = 4 << 20
+
+// Transcoder HTTP/JSON 与 gRPC 转码器
+// 请求被转换为进程内的 gRPC 调用交由 grpc.Server 处理, 因此会经过完整的拦截器链
+type Transcoder struct {
+	server    *grpc.Server
+	options   *TranscodingOptions
+	maxBody   int64
+	marshal   protojson.MarshalOptions
+	unmarshal protojson.UnmarshalOptions
+}
+
+// NewTranscoder 创建转码器, 请求体大小受 maxRecvMsgSize 限制, 与原生 gRPC 调用一致, 0 表示使用 gRPC 默认值 4MB
+func NewTranscoder(server *grpc.Server, options *TranscodingOptions, maxRecvMsgSize int) *Transcoder {
+	if maxRecvMsgSize <= 0 {
+		maxRecvMsgSize = defaultMaxRecvMsgSize
+	}
+
+	return &Transcoder{
+		server:  server,
+		options: options,
+		maxBody: int64(maxRecvMsgSize),
+		marshal: protojson.MarshalOptions{
+			UseProtoNames:   options.UseProtoNames,
+			EmitUnpopulated: options.EmitUnpopulated,
+		},
+		unmarshal: protojson.UnmarshalOptions{DiscardUnknown: true},
+	}
+}
+
+// Rules 根据已注册服务的描述符生成路由映射
+// 优先使用 google.api.http 注解, 未声明注解时按命名约定生成; 流式方法与 grpc.* 内置服务不会被暴露
+func (t *Transcoder) Rules() ([]*HttpRule, error) {
+	names := make([]string, 0)
+	for name := range t.server.GetServiceInfo() {
+		if strings.HasPrefix(name, "grpc.") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rules := make([]*HttpRule, 0)
+	for _, name := range names {
+		desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("service %s descriptor not found: %w", name, err)
+		}
+
+		service, ok := desc.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a service", name)
+		}
+
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			if method.IsStreamingClient() || method.IsStreamingServer() {
+				continue
+			}
+
+			methodRules, err := t.methodRules(service, method)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, methodRules...)
+		}
+	}
+
+	return rules, nil
+}
+
+// methodRules 生成单个方法的路由映射
+func (t *Transcoder) methodRules(service protoreflect.ServiceDescriptor, method protoreflect.MethodDescriptor) ([]*HttpRule, error) {
+	fullMethod := "/" + string(service.FullName()) + "/" + string(method.Name())
+
+	input, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("%s input type not found: %w", fullMethod, err)
+	}
+	output, err := protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName())
+	if err != nil {
+		return nil, fmt.Errorf("%s output type not found: %w", fullMethod, err)
+	}
+
+	annotation, _ := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+	if annotation == nil {
+		if !t.options.NamingConvention {
+			return nil, nil
+		}
+		return []*HttpRule{{
+			Method:     http.MethodPost,
+			Path:       t.options.Prefix + fullMethod,
+			FullMethod: fullMethod,
+			Body:       "*",
+			PathParams: map[string]string{},
+			input:      input,
+			output:     output,
+		}}, nil
+	}
+
+	bindings := append([]*annotations.HttpRule{annotation}, annotation.GetAdditionalBindings()...)
+	rules := make([]*HttpRule, 0, len(bindings))
+	for _, binding := range bindings {
+		rule, err := t.bindingRule(binding)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fullMethod, err)
+		}
+		rule.FullMethod, rule.input, rule.output = fullMethod, input, output
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// bindingRule 解析 google.api.http 注解
+func (t *Transcoder) bindingRule(binding *annotations.HttpRule) (*HttpRule, error) {
+	var method, template string
+
+	switch pattern := binding.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		method, template = http.MethodGet, pattern.Get
+	case *annotations.HttpRule_Post:
+		method, template = http.MethodPost, pattern.Post
+	case *annotations.HttpRule_Put:
+		method, template = http.MethodPut, pattern.Put
+	case *annotations.HttpRule_Delete:
+		method, template = http.MethodDelete, pattern.Delete
+	case *annotations.HttpRule_Patch:
+		method, template = http.MethodPatch, pattern.Patch
+	case *annotations.HttpRule_Custom:
+		method, template = pattern.Custom.GetKind(), pattern.Custom.GetPath()
+	default:
+		return nil, fmt.Errorf("http rule pattern is required")
+	}
+
+	path, params, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HttpRule{
+		Method:       method,
+		Path:         t.options.Prefix + path,
+		Body:         binding.GetBody(),
+		ResponseBody: binding.GetResponseBody(),
+		PathParams:   params,
+	}, nil
+}
+
+// parseTemplate 将路径模板转换为路由路径
+// 支持 {field}、{field=*} 与位于末尾的 {field=**}, 字段路径中的 . 在参数名中替换为 _
+func parseTemplate(template string) (string, map[string]string, error) {
+	params := make(map[string]string)
+	var path strings.Builder
+
+	for rest := template; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			path.WriteString(rest)
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("invalid path template %q", template)
+		}
+		end += start
+
+		path.WriteString(rest[:start])
+
+		field, pattern, _ := strings.Cut(rest[start+1:end], "=")
+		name := strings.ReplaceAll(field, ".", "_")
+		params[name] = field
+
+		switch pattern {
+		case "", "*":
+			path.WriteString(":" + name)
+		case "**":
+			if end != len(rest)-1 {
+				return "", nil, fmt.Errorf("** must be the last segment of path template %q", template)
+			}
+			path.WriteString("*" + name)
+		default:
+			return "", nil, fmt.Errorf("unsupported variable pattern %q in path template %q", pattern, template)
+		}
+
+		rest = rest[end+1:]
+	}
+
+	return path.String(), params, nil
+}
+
+// ServeHTTP 处理转码请求, params 为路径参数值(参数名 -> 值)
+func (t *Transcoder) ServeHTTP(w http.ResponseWriter, r *http.Request, rule *HttpRule, params map[string]string) {
+	req := rule.input.New()
+
+	if err := t.decodeRequest(w, r, rule, params, req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			t.writeStatus(w, http.StatusRequestEntityTooLarge, status.New(codes.ResourceExhausted, err.Error()))
+			return
+		}
+		t.writeError(w, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	payload, err := proto.Marshal(req.Interface())
+	if err != nil {
+		t.writeError(w, status.New(codes.Internal, err.Error()))
+		return
+	}
+
+	recorder := newResponseRecorder()
+	t.server.ServeHTTP(recorder, newGrpcRequest(r.Context(), r, rule.FullMethod, payload))
+
+	if retryAfter := recorder.header.Get("Retry-After"); retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+
+	st := recorder.status()
+	if st.Code() != codes.OK {
+		t.writeError(w, st)
+		return
+	}
+
+	resp := rule.output.New()
+	if err := proto.Unmarshal(recorder.message(), resp.Interface()); err != nil {
+		t.writeError(w, status.New(codes.Internal, err.Error()))
+		return
+	}
+
+	var out proto.Message = resp.Interface()
+	if rule.ResponseBody != "" {
+		field := resp.Descriptor().Fields().ByName(protoreflect.Name(rule.ResponseBody))
+		if field == nil || field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+			t.writeError(w, status.New(codes.Internal, "invalid response_body "+rule.ResponseBody))
+			return
+		}
+		out = resp.Get(field).Message().Interface()
+	}
+
+	data, err := t.marshal.Marshal(out)
+	if err != nil {
+		t.writeError(w, status.New(codes.Internal, err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// decodeRequest 按 请求体 -> 查询参数 -> 路径参数 的顺序填充请求消息, 后者覆盖前者
+// 请求体超过最大接收消息大小时返回 *http.MaxBytesError
+func (t *Transcoder) decodeRequest(w http.ResponseWriter, r *http.Request, rule *HttpRule, params map[string]string, req protoreflect.Message) error {
+	if rule.Body != "" && r.Body != nil {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, t.maxBody))
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(body)) > 0 {
+			target := req
+			if rule.Body != "*" {
+				field := req.Descriptor().Fields().ByName(protoreflect.Name(rule.Body))
+				if field == nil || field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+					return fmt.Errorf("invalid body field %s", rule.Body)
+				}
+				target = req.Mutable(field).Message()
+			}
+
+			if err := t.unmarshal.Unmarshal(body, target.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	// body 为 * 时所有字段均来自请求体
+	if rule.Body != "*" {
+		for key, values := range r.URL.Query() {
+			if err := setField(req, key, values...); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, value := range params {
+		field, ok := rule.PathParams[name]
+		if !ok {
+			continue
+		}
+		// ** 参数值带有前导 /
+		if err := setField(req, field, strings.TrimPrefix(value, "/")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setField 按字段路径设置字段值, 字段名可以是 proto 名称或 JSON 名称
+func setField(msg protoreflect.Message, path string, values ...string) error {
+	names := strings.Split(path, ".")
+
+	for i, name := range names {
+		fields := msg.Descriptor().Fields()
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil {
+			field = fields.ByJSONName(name)
+		}
+		if field == nil {
+			return fmt.Errorf("unknown field %s", path)
+		}
+
+		if i < len(names)-1 {
+			if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+				return fmt.Errorf("field %s is not a message", name)
+			}
+			msg = msg.Mutable(field).Message()
+			continue
+		}
+
+		if field.IsMap() || field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+			return fmt.Errorf("field %s cannot be set from a string", path)
+		}
+
+		if field.IsList() {
+			list := msg.Mutable(field).List()
+			for _, value := range values {
+				v, err := parseScalar(field, value)
+				if err != nil {
+					return fmt.Errorf("field %s: %w", path, err)
+				}
+				list.Append(v)
+			}
+			return nil
+		}
+
+		if len(values) == 0 {
+			return nil
+		}
+
+		v, err := parseScalar(field, values[len(values)-1])
+		if err != nil {
+			return fmt.Errorf("field %s: %w", path, err)
+		}
+		msg.Set(field, v)
+	}
+
+	return nil
+}
+
+// parseScalar 将字符串解析为标量字段值
+func parseScalar(field protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BytesKind:
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			b, err = base64.URLEncoding.DecodeString(value)
+		}
+		return protoreflect.ValueOfBytes(b), err
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(value, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(value, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(value, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(value, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.EnumKind:
+		if v := field.Enum().Values().ByName(protoreflect.Name(value)); v != nil {
+			return protoreflect.ValueOfEnum(v.Number()), nil
+		}
+		n, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported kind %s", field.Kind())
+	}
+}
+
+// writeError 以 google.rpc.Status JSON 形式输出错误
+func (t *Transcoder) writeError(w http.ResponseWriter, st *status.Status) {
+	t.writeStatus(w, HTTPStatusFromCode(st.Code()), st)
+}
+
+// writeStatus 以指定的 HTTP 状态码输出错误状态
+func (t *Transcoder) writeStatus(w http.ResponseWriter, code int, st *status.Status) {
+	data, err := t.marshal.Marshal(st.Proto())
+	if err != nil {
+		data = []byte(`{"code":13,"message":"failed to marshal error"}`)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
+
+// HTTPStatusFromCode gRPC 状态码对应的 HTTP 状态码
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// newGrpcRequest 构造进程内 gRPC 请求, HTTP 请求头作为元数据传递, 保留客户端地址
+func newGrpcRequest(ctx context.Context, r *http.Request, fullMethod string, payload []byte) *http.Request {
+	frame := make([]byte, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+
+	header := r.Header.Clone()
+	for _, key := range []string{"Content-Length", "Content-Encoding", "Accept-Encoding", "Connection"} {
+		header.Del(key)
+	}
+	header.Set("Content-Type", "application/grpc")
+
+	req := (&http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(frame)),
+		Host:       r.Host,
+		RemoteAddr: r.RemoteAddr,
+		RequestURI: fullMethod,
+		TLS:        r.TLS,
+	}).WithContext(ctx)
+
+	return req
+}
+
+// responseRecorder 记录 grpc.Server 的响应
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header)}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(int) {}
+
+func (r *responseRecorder) Flush() {}
+
+// status 从响应尾部解析调用状态
+func (r *responseRecorder) status() *status.Status {
+	if details := r.header.Get("Grpc-Status-Details-Bin"); details != "" {
+		data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(details, "="))
+		if err == nil {
+			st := &spb.Status{}
+			if proto.Unmarshal(data, st) == nil {
+				return status.FromProto(st)
+			}
+		}
+	}
+
+	// server 已停止时 ServeHTTP 不写入任何响应
+	value := r.header.Get("Grpc-Status")
+	if value == "" {
+		if len(r.header) == 0 && r.body.Len() == 0 {
+			return status.New(codes.Unavailable, "grpc server is stopped")
+		}
+		return status.New(codes.Internal, "missing grpc status")
+	}
+
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return status.New(codes.Internal, "malformed grpc status")
+	}
+
+	message, _ := url.PathUnescape(r.header.Get("Grpc-Message"))
+	return status.New(codes.Code(code), message)
+}
+
+// message 返回响应中的第一条消息
+func (r *responseRecorder) message() []byte {
+	data := r.body.Bytes()
+	if len(data) < 5 {
+		return nil
+	}
+
+	length := binary.BigEndian.Uint32(data[1:5])
+	if uint32(len(data)-5) < length {
+		return nil
+	}
+
+	return data[5 : 5+length]
+}
@@ -0,0 +1,16 @@
+package rpc
+
+// TranscodingOptions HTTP/JSON 转码选项
+type TranscodingOptions struct {
+	Prefix           string // 路由前缀, 如 /api
+	NamingConvention bool   // 未声明 google.api.http 注解的一元方法按 POST {Prefix}/{package.Service}/{Method} 暴露
+	UseProtoNames    bool   // JSON 字段使用 proto 原始名称, 默认使用 lowerCamelCase
+	EmitUnpopulated  bool   // 响应中输出零值字段
+}
+
+// NewTranscodingOptions 创建转码选项, 默认启用命名约定
+func NewTranscodingOptions() *TranscodingOptions {
+	return &TranscodingOptions{
+		NamingConvention: true,
+	}
+}
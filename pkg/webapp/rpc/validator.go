@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validatorAll 校验全部字段的消息(protoc-gen-validate 生成)
+type validatorAll interface {
+	ValidateAll() error
+}
+
+// validator 校验消息
+type validator interface {
+	Validate() error
+}
+
+// Validator gRPC 请求校验拦截器
+// 请求消息实现 ValidateAll() error 或 Validate() error 时进行校验, 失败返回 InvalidArgument
+type Validator struct{}
+
+// NewValidator 初始化 gRPC 请求校验拦截器
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+// Unary 校验一元调用请求
+func (v *Validator) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := validate(req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream 校验流式调用中接收的每条消息
+func (v *Validator) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss})
+	}
+}
+
+// validatingStream 接收消息后进行校验的 grpc.ServerStream
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return validate(m)
+}
+
+func validate(m any) error {
+	var err error
+
+	switch v := m.(type) {
+	case validatorAll:
+		err = v.ValidateAll()
+	case validator:
+		err = v.Validate()
+	}
+
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package versioning
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+var (
+	// ErrInvalidVersion 版本格式错误
+	ErrInvalidVersion = errors.New("invalid api version")
+	// ErrAmbiguousVersion 请求以不同方式声明了不同的版本
+	ErrAmbiguousVersion = errors.New("ambiguous api version")
+	// ErrUnspecifiedVersion 请求未指定版本且不使用默认版本
+	ErrUnspecifiedVersion = errors.New("api version is required")
+)
+
+// versionPattern 版本格式: 主版本[.次版本] 或 日期(2024-01-01)
+var versionPattern = regexp.MustCompile(`^(\d+(\.\d+)?|\d{4}-\d{2}-\d{2})$`)
+
+// ApiVersioning API 版本管理实现
+type ApiVersioning struct {
+	opts    *Options
+	readers []Reader
+}
+
+// NewApiVersioning 创建 API 版本管理
+func NewApiVersioning(opts *Options) *ApiVersioning {
+	readers := opts.readers
+	if len(readers) == 0 {
+		readers = []Reader{queryReader{name: "api-version"}}
+	}
+
+	return &ApiVersioning{
+		opts:    opts,
+		readers: readers,
+	}
+}
+
+// Read 读取请求的版本
+// 多种方式声明了不同版本时返回 ErrAmbiguousVersion, 未声明时按配置返回默认版本或 ErrUnspecifiedVersion
+func (v *ApiVersioning) Read(r *http.Request) (string, error) {
+	var version string
+	for _, reader := range v.readers {
+		for _, value := range reader.Read(r) {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if !Valid(value) {
+				return "", ErrInvalidVersion
+			}
+
+			value = Normalize(value)
+			if version != "" && version != value {
+				return "", ErrAmbiguousVersion
+			}
+			version = value
+		}
+	}
+
+	if version != "" {
+		return version, nil
+	}
+	if v.opts.AssumeDefaultVersionWhenUnspecified && v.opts.DefaultVersion != "" {
+		return Normalize(v.opts.DefaultVersion), nil
+	}
+	return "", ErrUnspecifiedVersion
+}
+
+// Deprecation 返回版本弃用策略
+func (v *ApiVersioning) Deprecation(version string) (web.VersionPolicy, bool) {
+	policy, ok := v.opts.deprecations[Normalize(version)]
+	return policy, ok
+}
+
+// Sunset 返回版本下线策略
+func (v *ApiVersioning) Sunset(version string) (web.VersionPolicy, bool) {
+	policy, ok := v.opts.sunsets[Normalize(version)]
+	return policy, ok
+}
+
+// ReportApiVersions 是否在响应中报告版本
+func (v *ApiVersioning) ReportApiVersions() bool {
+	return v.opts.ReportApiVersions
+}
+
+// Valid 版本格式是否正确, 允许 v 前缀
+func Valid(version string) bool {
+	return versionPattern.MatchString(strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V"))
+}
+
+// Normalize 规范化版本, 去除 v 前缀与为 0 的次版本, 如 v2.0 为 2
+func Normalize(version string) string {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if major, minor, ok := strings.Cut(version, "."); ok && strings.Trim(minor, "0") == "" {
+		return major
+	}
+	return version
+}
@@ -0,0 +1,80 @@
+package versioning
+
+import (
+	"time"
+
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// Options API 版本配置
+type Options struct {
+	DefaultVersion                      string // 默认版本
+	AssumeDefaultVersionWhenUnspecified bool   // 请求未指定版本时使用默认版本, 否则返回 400
+	ReportApiVersions                   bool   // 在版本化路由的响应中输出 api-supported-versions 与 api-deprecated-versions 头
+	readers                             []Reader
+	deprecations                        map[string]web.VersionPolicy
+	sunsets                             map[string]web.VersionPolicy
+}
+
+// NewOptions 返回默认配置, 未添加读取方式时从查询参数 api-version 读取
+func NewOptions() *Options {
+	return &Options{
+		DefaultVersion:    "1",
+		ReportApiVersions: true,
+		deprecations:      make(map[string]web.VersionPolicy),
+		sunsets:           make(map[string]web.VersionPolicy),
+	}
+}
+
+// ReadFromUrlSegment 从 URL 路径段读取版本, 如 /api/v2/users
+func (o *Options) ReadFromUrlSegment() *Options {
+	o.readers = append(o.readers, urlSegmentReader{})
+	return o
+}
+
+// ReadFromQuery 从查询参数读取版本, 默认参数名为 api-version
+func (o *Options) ReadFromQuery(name ...string) *Options {
+	reader := queryReader{name: "api-version"}
+	if len(name) > 0 {
+		reader.name = name[0]
+	}
+	o.readers = append(o.readers, reader)
+	return o
+}
+
+// ReadFromHeader 从请求头读取版本, 默认请求头为 X-Api-Version
+func (o *Options) ReadFromHeader(names ...string) *Options {
+	if len(names) == 0 {
+		names = []string{"X-Api-Version"}
+	}
+	o.readers = append(o.readers, headerReader{names: names})
+	return o
+}
+
+// ReadFromMediaType 从 Accept 与 Content-Type 的媒体类型参数读取版本, 如 application/json; v=2, 默认参数名为 v
+func (o *Options) ReadFromMediaType(param ...string) *Options {
+	reader := mediaTypeReader{param: "v"}
+	if len(param) > 0 {
+		reader.param = param[0]
+	}
+	o.readers = append(o.readers, reader)
+	return o
+}
+
+// AddReader 添加自定义读取方式
+func (o *Options) AddReader(reader Reader) *Options {
+	o.readers = append(o.readers, reader)
+	return o
+}
+
+// AddDeprecationPolicy 配置版本弃用时间与说明地址, 弃用的版本由路由声明, 未配置时弃用头为 true
+func (o *Options) AddDeprecationPolicy(version string, date time.Time, link string) *Options {
+	o.deprecations[Normalize(version)] = web.VersionPolicy{Date: date, Link: link}
+	return o
+}
+
+// AddSunsetPolicy 配置版本下线时间与说明地址, 请求该版本时输出 Sunset 头
+func (o *Options) AddSunsetPolicy(version string, date time.Time, link string) *Options {
+	o.sunsets[Normalize(version)] = web.VersionPolicy{Date: date, Link: link}
+	return o
+}
@@ -0,0 +1,76 @@
+package versioning
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Reader 版本读取方式, 返回请求中声明的全部版本
+type Reader interface {
+	Read(r *http.Request) []string
+}
+
+// urlSegmentReader 从 v 开头的路径段读取版本
+type urlSegmentReader struct{}
+
+func (urlSegmentReader) Read(r *http.Request) []string {
+	for _, segment := range strings.Split(r.URL.Path, "/") {
+		if len(segment) < 2 || (segment[0] != 'v' && segment[0] != 'V') {
+			continue
+		}
+		if Valid(segment[1:]) {
+			return []string{segment[1:]}
+		}
+	}
+	return nil
+}
+
+// queryReader 从查询参数读取版本
+type queryReader struct {
+	name string
+}
+
+func (q queryReader) Read(r *http.Request) []string {
+	return r.URL.Query()[q.name]
+}
+
+// headerReader 从请求头读取版本
+type headerReader struct {
+	names []string
+}
+
+func (h headerReader) Read(r *http.Request) []string {
+	var versions []string
+	for _, name := range h.names {
+		for _, value := range r.Header.Values(name) {
+			for _, version := range strings.Split(value, ",") {
+				versions = append(versions, strings.TrimSpace(version))
+			}
+		}
+	}
+	return versions
+}
+
+// mediaTypeReader 从媒体类型参数读取版本
+type mediaTypeReader struct {
+	param string
+}
+
+func (m mediaTypeReader) Read(r *http.Request) []string {
+	var versions []string
+	for _, header := range []string{"Accept", "Content-Type"} {
+		for _, value := range r.Header.Values(header) {
+			for _, mediaType := range strings.Split(value, ",") {
+				_, params, err := mime.ParseMediaType(strings.TrimSpace(mediaType))
+				if err != nil {
+					continue
+				}
+				if version, ok := params[m.param]; ok {
+					versions = append(versions, version)
+				}
+			}
+		}
+	}
+	return versions
+}
@@ -0,0 +1,17 @@
+package web
+
+import "github.com/gin-gonic/gin"
+
+// AntiforgeryTokens 防伪令牌, 请求令牌以请求头或表单字段随非安全请求提交
+type AntiforgeryTokens struct {
+	RequestToken  string `json:"requestToken"`
+	HeaderName    string `json:"headerName"`
+	FormFieldName string `json:"formFieldName"`
+}
+
+// Antiforgery 防伪令牌服务
+type Antiforgery interface {
+	GetAndStoreTokens(c *gin.Context) AntiforgeryTokens // 返回请求令牌, cookie 令牌不存在或无效时生成并写出 cookie
+	Validate(c *gin.Context) error                      // 校验请求令牌, 失败时返回 403 错误
+	EnsureCookie(c *gin.Context)                        // 安全方法的请求调用, 前端脚本需读取 cookie 时在其不存在时写出
+}
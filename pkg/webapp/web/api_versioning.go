@@ -0,0 +1,38 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ContextApiVersionKey 请求的 API 版本在 gin 上下文中的键
+const ContextApiVersionKey = "apiVersion"
+
+// VersionPolicy 版本弃用或下线策略
+type VersionPolicy struct {
+	Date time.Time // 弃用或下线时间, 为零值时弃用头为 true
+	Link string    // 说明文档地址, 以 Link 头输出
+}
+
+// ApiVersioning API 版本管理
+type ApiVersioning interface {
+	Read(r *http.Request) (string, error)             // 读取请求的版本, 未指定时返回默认版本, 无默认版本时返回空
+	Deprecation(version string) (VersionPolicy, bool) // 版本弃用策略
+	Sunset(version string) (VersionPolicy, bool)      // 版本下线策略
+	ReportApiVersions() bool                          // 是否在响应中报告支持与弃用的版本
+}
+
+// apiVersionKey API 版本在 context 中的键
+type apiVersionKey struct{}
+
+// WithApiVersion 将请求的 API 版本存入 context
+func WithApiVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+// ApiVersionFromContext 从 context 中获取请求的 API 版本, 返回 false 表示未指定版本
+func ApiVersionFromContext(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(apiVersionKey{}).(string)
+	return version, ok && version != ""
+}
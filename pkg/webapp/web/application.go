@@ -12,18 +12,37 @@ type Application interface {
 	Logger() *zap.Logger
 	Config() *viper.Viper
 	Env() *Environment
+	Addresses() []string
 	Use(...any) Application
 	UseSwagger() Application
 	UseCORS(any) Application
+	UseCorsPolicies() Application
+	UseSecurityHeaders(...func(*SecurityHeadersOptions)) Application
 	UseStaticFiles(urlPath, root string) Application
 	UseHealthCheck() Application
 	UseAuthentication() Application
 	UseAuthorization() Application
 	UseRecovery() Application
+	UseExceptionHandler() Application
 	UseLogger() Application
 	UseLocalization() Application
 	UseRateLimiter() Application
+	UseGrpcAuthentication() Application
+	UseGrpcAuthorization() Application
+	UseGrpcRateLimiter() Application
+	UseGrpcInterceptor(...any) Application
 	UseRequestDecompression() Application
+	UseResponseCompression() Application
+	UseOpenApi() Application
+	UseApiVersioning() Application
+	UseRouteDiagnostics() Application
+	UseOutputCache() Application
+	UseHttpCaching() Application
+	UseIdempotency() Application
+	UseAntiforgery() Application
 	MapRoute(...any) Application
+	MapGroup(prefix string) *GroupRouteConfig
+	MapWebSocket(path string, handler any) *RouteConfig
+	Routes() []*RouteConfig
 	MapGrpcServices(...any) Application
 }
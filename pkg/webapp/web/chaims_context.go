@@ -0,0 +1,17 @@
+package web
+
+import "context"
+
+// claimsPrincipalKey ClaimsPrincipal 在 context 中的键
+type claimsPrincipalKey struct{}
+
+// WithClaimsPrincipal 将 ClaimsPrincipal 存入 context
+func WithClaimsPrincipal(ctx context.Context, principal *ClaimsPrincipal) context.Context {
+	return context.WithValue(ctx, claimsPrincipalKey{}, principal)
+}
+
+// ClaimsPrincipalFromContext 从 context 中获取 ClaimsPrincipal, 返回 false 表示未认证
+func ClaimsPrincipalFromContext(ctx context.Context) (*ClaimsPrincipal, bool) {
+	principal, ok := ctx.Value(claimsPrincipalKey{}).(*ClaimsPrincipal)
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"net/http"
+	"time"
+)
+
+// CorsPolicy 跨域策略
+type CorsPolicy struct {
+	AllowOrigins     []string      `mapstructure:"allow_origins"`     // 允许的来源, * 表示全部, 支持 https://*.example.com 匹配子域名
+	AllowMethods     []string      `mapstructure:"allow_methods"`     // 允许的请求方法, 为空时允许常用方法
+	AllowHeaders     []string      `mapstructure:"allow_headers"`     // 允许的请求头, * 表示全部
+	ExposeHeaders    []string      `mapstructure:"expose_headers"`    // 允许前端脚本读取的响应头
+	AllowCredentials bool          `mapstructure:"allow_credentials"` // 是否允许携带 cookie 等凭据
+	MaxAge           time.Duration `mapstructure:"max_age"`           // 预检结果缓存时间
+	ValidateOrigin   bool          `mapstructure:"validate_origin"`   // 来源不在静态列表中时交由 CorsOriginValidator 校验, 用于按租户动态配置来源
+
+	AllowOriginFunc func(origin string) bool `mapstructure:"-"` // 来源不在静态列表中时的自定义校验
+}
+
+// CorsOriginValidator 动态来源校验, 如按租户查询允许的来源
+type CorsOriginValidator interface {
+	ValidateOrigin(r *http.Request, policy, origin string) (bool, error)
+}
+
+// Cors 跨域策略管理
+type Cors interface {
+	Policy(name string) (*CorsPolicy, bool) // 跨域策略, 名称为空时返回默认策略
+}
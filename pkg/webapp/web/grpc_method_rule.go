@@ -0,0 +1,55 @@
+package web
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// GrpcMethodRule gRPC 方法匹配规则
+type GrpcMethodRule struct {
+	RouteKey
+	Values []string // 匹配后生效的配置项, 如鉴权方案、授权策略、限流策略
+}
+
+// GrpcMethodRules 配置的全部 gRPC 方法规则, 用于启动时校验引用的鉴权方案、授权策略与限流策略
+type GrpcMethodRules struct {
+	Schemes    []GrpcMethodRule // 方法鉴权方案
+	Policies   []GrpcMethodRule // 方法授权策略
+	RateLimits []GrpcMethodRule // 方法限流策略
+}
+
+// NewGrpcMethodRule 创建 gRPC 方法匹配规则
+// pattern 为完整方法名, 支持通配符, 如 /hello.Greeter/SayHello、/hello.Greeter/*
+func NewGrpcMethodRule(pattern string, values ...string) GrpcMethodRule {
+	if !strings.HasPrefix(pattern, "/") {
+		panic("grpc method pattern must begin with '/': " + pattern)
+	}
+
+	g, err := glob.Compile(pattern, '/')
+	if err != nil {
+		panic("invalid grpc method pattern " + pattern + ": " + err.Error())
+	}
+
+	return GrpcMethodRule{
+		RouteKey: RouteKey{Path: pattern, Glob: g},
+		Values:   values,
+	}
+}
+
+// MatchGrpcMethod 返回所有匹配规则的配置项, bool 表示是否有规则匹配
+func MatchGrpcMethod(rules []GrpcMethodRule, fullMethod string) ([]string, bool) {
+	var (
+		values  []string
+		matched bool
+	)
+
+	for _, rule := range rules {
+		if rule.Glob.Match(fullMethod) {
+			values = append(values, rule.Values...)
+			matched = true
+		}
+	}
+
+	return values, matched
+}
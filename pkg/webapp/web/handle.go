@@ -0,0 +1,269 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+)
+
+// maxMultipartMemory multipart 表单解析时保存在内存中的最大字节数
+const maxMultipartMemory = 32 << 20
+
+// StatusCoder 响应实现该接口时以其返回值作为响应状态码
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Handle 将类型化处理函数适配为 gin.HandlerFunc
+// Req 必须为结构体, 依次从请求体(json/form 标签)、请求头(header 标签)、查询参数(query 标签)与路径参数(path 标签)绑定,
+// 随后按 validate 标签校验; 处理函数返回的 Resp 以 JSON 写出, 为 nil 时返回 204;
+// Resp 实现 EntityTagger 或 LastModifier 时写出 ETag 与 Last-Modified 并处理条件请求, 处理函数可通过 ValidatePreconditions 校验 If-Match;
+// 绑定、校验与处理函数返回的错误在启用异常处理中间件时渲染为 Problem Details
+func Handle[Req any, Resp any](fn func(ctx context.Context, req Req) (Resp, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req Req
+		if err := bind(c, &req); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		if reflect.Indirect(reflect.ValueOf(&req)).Kind() == reflect.Struct {
+			if err := ValidateStruct(&req, c.GetHeader("Accept-Language")); err != nil {
+				abortWithError(c, err)
+				return
+			}
+		}
+
+		resp, err := fn(withRequestHeader(c.Request.Context(), c.Request.Header), req)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		writeResponse(c, resp)
+	}
+}
+
+// MapHandle 以类型化处理函数注册分组路由, 请求与响应模型同时记录到路由的文档元数据
+func MapHandle[Req any, Resp any](group *GroupRouteConfig, method RequestMethod, path string,
+	fn func(ctx context.Context, req Req) (Resp, error)) *RouteConfig {
+
+	route := group.Map(method, path, Handle(fn))
+	route.Request = reflect.TypeFor[Req]()
+
+	typ := reflect.TypeFor[Resp]()
+	route.Responses = append(route.Responses, ResponseType{Status: declaredStatus(typ), Type: typ})
+
+	return route
+}
+
+// declaredStatus 返回响应类型声明的状态码, 指针类型在非 nil 的零值上调用, 避免值接收者方法解引用 nil 指针
+func declaredStatus(typ reflect.Type) int {
+	statusCoder := reflect.TypeFor[StatusCoder]()
+	if typ.Kind() == reflect.Interface || !typ.Implements(statusCoder) {
+		return http.StatusOK
+	}
+
+	value := reflect.Zero(typ)
+	if typ.Kind() == reflect.Pointer {
+		value = reflect.New(typ.Elem())
+	}
+	return value.Interface().(StatusCoder).StatusCode()
+}
+
+// bind 绑定请求, 路径参数优先级最高
+func bind(c *gin.Context, ptr any) error {
+	if err := bindBody(c, ptr); err != nil {
+		return err
+	}
+
+	if len(c.Request.Header) > 0 {
+		headers := make(map[string][]string, len(c.Request.Header)*2)
+		for key, values := range c.Request.Header {
+			headers[key] = values
+			headers[strings.ToLower(key)] = values
+		}
+		if err := mapTagged(ptr, headers, "header"); err != nil {
+			return problem.BadRequest(err.Error()).WithError(err)
+		}
+	}
+
+	if query := c.Request.URL.Query(); len(query) > 0 {
+		if err := mapTagged(ptr, query, "query"); err != nil {
+			return problem.BadRequest(err.Error()).WithError(err)
+		}
+	}
+
+	if len(c.Params) > 0 {
+		params := make(map[string][]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = []string{param.Value}
+		}
+		if err := mapTagged(ptr, params, "path"); err != nil {
+			return problem.BadRequest(err.Error()).WithError(err)
+		}
+	}
+
+	return nil
+}
+
+// mapTagged 仅使用声明了 tag 标签的键绑定, 避免未声明标签的字段按字段名被请求参数覆盖
+func mapTagged(ptr any, source map[string][]string, tag string) error {
+	names := tagNames(reflect.TypeOf(ptr), tag)
+	if len(names) == 0 {
+		return nil
+	}
+
+	values := make(map[string][]string, len(names))
+	for name := range names {
+		if value, ok := source[name]; ok {
+			values[name] = value
+		}
+	}
+
+	return binding.MapFormWithTag(ptr, values, tag)
+}
+
+// tagNames 返回类型中 tag 标签声明的全部名称, 包含嵌套结构体
+func tagNames(t reflect.Type, tag string) map[string]struct{} {
+	key := tagNamesKey{t: t, tag: tag}
+	if names, ok := tagNamesCache.Load(key); ok {
+		return names.(map[string]struct{})
+	}
+
+	names := make(map[string]struct{})
+	collectTagNames(t, tag, names, map[reflect.Type]bool{})
+	tagNamesCache.Store(key, names)
+	return names
+}
+
+func collectTagNames(t reflect.Type, tag string, names map[string]struct{}, visited map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return
+	}
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]; name != "" && name != "-" {
+			names[name] = struct{}{}
+		}
+		collectTagNames(field.Type, tag, names, visited)
+	}
+}
+
+type tagNamesKey struct {
+	t   reflect.Type
+	tag string
+}
+
+var tagNamesCache sync.Map
+
+// bindBody 按 Content-Type 绑定请求体, 支持 JSON 与表单
+func bindBody(c *gin.Context, ptr any) error {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		return nil
+	}
+
+	var err error
+	switch contentType := c.ContentType(); {
+	case contentType == "" || contentType == binding.MIMEJSON || strings.HasSuffix(contentType, "+json"):
+		err = json.NewDecoder(c.Request.Body).Decode(ptr)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case contentType == binding.MIMEPOSTForm:
+		if err = c.Request.ParseForm(); err == nil {
+			err = binding.MapFormWithTag(ptr, c.Request.PostForm, "form")
+		}
+	case contentType == binding.MIMEMultipartPOSTForm:
+		if err = c.Request.ParseMultipartForm(maxMultipartMemory); err == nil {
+			err = binding.MapFormWithTag(ptr, c.Request.MultipartForm.Value, "form")
+		}
+	default:
+		return problem.UnsupportedMediaType("unsupported content type " + contentType)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return problem.PayloadTooLarge("").WithError(err)
+	}
+	return problem.BadRequest("invalid request body").WithError(err)
+}
+
+// writeResponse 写出响应
+func writeResponse(c *gin.Context, resp any) {
+	value := reflect.ValueOf(resp)
+	if !value.IsValid() || (value.Kind() == reflect.Pointer && value.IsNil()) {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	status := http.StatusOK
+	if coder, ok := resp.(StatusCoder); ok {
+		status = coder.StatusCode()
+	}
+
+	var etag string
+	var lastModified time.Time
+	if tagger, ok := resp.(EntityTagger); ok {
+		etag = tagger.ETag()
+	}
+	if modifier, ok := resp.(LastModifier); ok {
+		lastModified = modifier.LastModified()
+	}
+	if (etag != "" || !lastModified.IsZero()) && NotModified(c, etag, lastModified) {
+		return
+	}
+
+	c.JSON(status, resp)
+}
+
+// AbortWithError 中止请求并记录错误, 供处理函数之外的组件(如反向代理)以与 Handle 相同的方式输出错误
+func AbortWithError(c *gin.Context, err error) {
+	abortWithError(c, err)
+}
+
+// abortWithError 中止请求并记录错误
+// 启用异常处理中间件时由其渲染, 否则按 problem.Error 的状态码写出简单的错误响应
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+
+	if _, ok := c.Get(ContextExceptionHandlerKey); ok {
+		return
+	}
+
+	e, ok := problem.As(err)
+	if !ok {
+		e = problem.Internal(err)
+	}
+
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+
+	body := gin.H{"error": message}
+	if len(e.Errors) > 0 {
+		body["errors"] = e.Errors
+	}
+	c.JSON(e.Status, body)
+}
@@ -0,0 +1,35 @@
+package web
+
+import (
+	"context"
+	"time"
+)
+
+// HealthStatus 健康状态
+type HealthStatus string
+
+const (
+	Healthy   HealthStatus = "ok"        // 健康
+	Unhealthy HealthStatus = "unhealthy" // 不健康
+)
+
+// HealthEntry 单项健康检查结果
+type HealthEntry struct {
+	Status   HealthStatus  `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// HealthReport 健康检查报告
+type HealthReport struct {
+	Status  HealthStatus           `json:"status"`
+	Entries map[string]HealthEntry `json:"entries,omitempty"`
+}
+
+// HealthCheck 健康检查接口
+type HealthCheck interface {
+	Check(ctx context.Context, names ...string) HealthReport // 执行健康检查, 不指定名称时执行全部检查
+	Has(name string) bool                                    // 是否注册了指定名称的检查
+	Ready() bool                                             // 是否就绪(可接收流量)
+	SetReady(ready bool)                                     // 设置就绪状态
+}
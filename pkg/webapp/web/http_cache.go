@@ -0,0 +1,225 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+)
+
+// ETagKind ETag 类型
+type ETagKind int
+
+const (
+	ETagNone   ETagKind = iota // 不生成 ETag
+	ETagStrong                 // 强 ETag, 逐字节相同才视为相同, 可用于 If-Match
+	ETagWeak                   // 弱 ETag, 语义相同即视为相同, 仅用于 If-None-Match
+)
+
+// CacheControl Cache-Control 响应头策略
+type CacheControl struct {
+	MaxAge               time.Duration // max-age, 客户端缓存时间
+	SharedMaxAge         time.Duration // s-maxage, 共享缓存(CDN、代理)缓存时间
+	StaleWhileRevalidate time.Duration // stale-while-revalidate, 过期后仍可使用并在后台验证的时间
+	Public               bool          // 允许共享缓存
+	Private              bool          // 仅允许客户端缓存
+	NoCache              bool          // 使用缓存前须向服务端验证
+	NoStore              bool          // 禁止缓存
+	MustRevalidate       bool          // 过期后须向服务端验证
+	Immutable            bool          // 有效期内内容不会变化, 客户端无需验证
+}
+
+// String 生成 Cache-Control 响应头
+func (cc CacheControl) String() string {
+	var directives []string
+	if cc.Public {
+		directives = append(directives, "public")
+	}
+	if cc.Private {
+		directives = append(directives, "private")
+	}
+	if cc.NoCache {
+		directives = append(directives, "no-cache")
+	}
+	if cc.NoStore {
+		directives = append(directives, "no-store")
+	}
+	if cc.MaxAge > 0 {
+		directives = append(directives, "max-age="+seconds(cc.MaxAge))
+	}
+	if cc.SharedMaxAge > 0 {
+		directives = append(directives, "s-maxage="+seconds(cc.SharedMaxAge))
+	}
+	if cc.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+seconds(cc.StaleWhileRevalidate))
+	}
+	if cc.MustRevalidate {
+		directives = append(directives, "must-revalidate")
+	}
+	if cc.Immutable {
+		directives = append(directives, "immutable")
+	}
+	if len(directives) == 0 {
+		return "max-age=0"
+	}
+	return strings.Join(directives, ", ")
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// EntityTagger 响应实现该接口时 Handle 以其返回值作为 ETag 并处理条件请求
+type EntityTagger interface {
+	ETag() string
+}
+
+// LastModifier 响应实现该接口时 Handle 以其返回值作为 Last-Modified 并处理条件请求
+type LastModifier interface {
+	LastModified() time.Time
+}
+
+// ComputeETag 按内容生成 ETag
+func ComputeETag(data []byte, kind ETagKind) string {
+	sum := sha256.Sum256(data)
+	tag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	if kind == ETagWeak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// JSONETag 按 JSON 序列化结果生成 ETag, 与 c.JSON 写出相同对象时的响应体 ETag 一致
+func JSONETag(v any, kind ETagKind) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return ComputeETag(data, kind), nil
+}
+
+// FormatETag 规范化 ETag, 未加引号的值视为强 ETag 并加上引号, 如版本号 42 转换为 "42"
+func FormatETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// NotModified 写出 ETag 与 Last-Modified, GET、HEAD 请求的 If-None-Match 或 If-Modified-Since 表明客户端缓存仍有效时
+// 以 304 中止请求并返回 true; etag 为空或 lastModified 为零值时不参与判断
+func NotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	etag = FormatETag(etag)
+	setValidators(c.Writer.Header(), etag, lastModified)
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	if !notModified(c.Request.Header, etag, lastModified) {
+		return false
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// PreconditionFailed 校验 If-Match 与 If-Unmodified-Since, 资源已被修改时以 412 中止请求并返回 true
+// etag 与 lastModified 为资源的当前版本, 用于 PUT、PATCH、DELETE 的乐观并发控制
+func PreconditionFailed(c *gin.Context, etag string, lastModified time.Time) bool {
+	if err := checkPreconditions(c.Request.Header, FormatETag(etag), lastModified); err != nil {
+		abortWithError(c, err)
+		return true
+	}
+	return false
+}
+
+// ValidatePreconditions 在 Handle 处理函数中校验 If-Match 与 If-Unmodified-Since, 资源已被修改时返回 412 错误
+func ValidatePreconditions(ctx context.Context, etag string, lastModified time.Time) error {
+	header, _ := ctx.Value(requestHeaderKey{}).(http.Header)
+	if header == nil {
+		return nil
+	}
+	return checkPreconditions(header, FormatETag(etag), lastModified)
+}
+
+// requestHeaderKey Handle 处理函数上下文中保存请求头的键
+type requestHeaderKey struct{}
+
+// withRequestHeader 在上下文中保存请求头, 供 ValidatePreconditions 使用
+func withRequestHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, requestHeaderKey{}, header)
+}
+
+// setValidators 写出 ETag 与 Last-Modified 响应头
+func setValidators(header http.Header, etag string, lastModified time.Time) {
+	if etag != "" {
+		header.Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// IsNotModified 按 If-None-Match 与 If-Modified-Since 判断客户端缓存是否仍有效, If-None-Match 存在时忽略 If-Modified-Since
+func IsNotModified(header http.Header, etag string, lastModified time.Time) bool {
+	return notModified(header, FormatETag(etag), lastModified)
+}
+
+func notModified(header http.Header, etag string, lastModified time.Time) bool {
+	if inm := header.Get("If-None-Match"); inm != "" {
+		return etag != "" && matchETag(inm, etag, false)
+	}
+
+	if ims := header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// checkPreconditions 按 If-Match 与 If-Unmodified-Since 校验资源版本, If-Match 存在时忽略 If-Unmodified-Since
+func checkPreconditions(header http.Header, etag string, lastModified time.Time) error {
+	if im := header.Get("If-Match"); im != "" {
+		if etag == "" || !matchETag(im, etag, true) {
+			return problem.PreconditionFailed("the resource has been modified")
+		}
+		return nil
+	}
+
+	if ius := header.Get("If-Unmodified-Since"); ius != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ius)
+		if err == nil && lastModified.Truncate(time.Second).After(since) {
+			return problem.PreconditionFailed("the resource has been modified")
+		}
+	}
+	return nil
+}
+
+// matchETag 判断条件请求头中的 ETag 列表是否包含 etag, strong 为 true 时使用强比较, 弱 ETag 不匹配
+func matchETag(list, etag string, strong bool) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	if strong && strings.HasPrefix(etag, "W/") {
+		return false
+	}
+
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strong && strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package web
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyStore 幂等记录存储, Reserve 须为原子操作以保证同一键只有一个请求执行
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) // 键不存在时写入并返回 true, 否则返回已保存的记录与 false
+	Complete(ctx context.Context, key string, value []byte, ttl time.Duration) error                // 保存处理结果
+	Release(ctx context.Context, key string) error                                                  // 删除记录, 允许以相同的键重试
+}
+
+// Idempotency 幂等性配置
+type Idempotency interface {
+	HeaderName() string             // 幂等键请求头
+	RequireKey() bool               // 缺少幂等键时是否拒绝请求
+	MaxKeyLength() int              // 幂等键最大长度
+	Expire() time.Duration          // 处理结果的保存时间
+	InFlightTimeout() time.Duration // 处理中记录的保存时间, 实例异常退出后到期释放
+	MaxBodySize() int64             // 可保存的最大响应体字节数
+	MaxRequestBodySize() int64      // 计算指纹时可读取的最大请求体字节数
+}
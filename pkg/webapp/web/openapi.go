@@ -0,0 +1,19 @@
+package web
+
+import "net/http"
+
+const (
+	OpenApiUIScriptPath = "/ui.js"  // 文档页面启动脚本地址, 相对于 UIPath
+	OpenApiUIAssetsPath = "/assets" // 文档页面内置静态资源地址, 相对于 UIPath
+)
+
+// OpenApi OpenAPI 文档, 在应用启动后由路由表生成
+type OpenApi interface {
+	Documents() []string                 // 文档名称, 第一个为默认文档
+	Document(name string) ([]byte, bool) // 文档 JSON
+	Path() string                        // 文档地址前缀, 文档地址为 {Path}/{name}.json, 默认文档同时提供于 {Path}.json
+	UIPath() string                      // 文档页面地址, 为空时不提供页面
+	UIPage() []byte                      // 文档页面, 不包含内联脚本
+	UIScript() []byte                    // 文档页面启动脚本, 提供于 {UIPath}/ui.js
+	UIFiles() http.FileSystem            // 文档页面内置静态资源, 提供于 {UIPath}/assets/, 为 nil 时不提供
+}
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"context"
+	"time"
+)
+
+// OutputCachePolicy 输出缓存策略
+type OutputCachePolicy struct {
+	Expire           time.Duration // 缓存时间
+	VaryByQuery      []string      // 按查询参数区分缓存, * 表示全部参数, 为 nil 时默认为 *, 空切片表示不区分
+	VaryByHeader     []string      // 按请求头区分缓存
+	VaryByRouteValue []string      // 按路径参数区分缓存
+	VaryByUser       bool          // 按用户区分缓存, 未开启时已认证的请求不缓存
+	Tags             []string      // 缓存标签, 用于按标签清除缓存
+}
+
+// OutputCacheStore 输出缓存存储
+type OutputCacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)                                       // 读取缓存, 不存在时返回 nil
+	Set(ctx context.Context, key string, value []byte, tags []string, ttl time.Duration) error // 写入缓存并关联标签
+	EvictByTag(ctx context.Context, tag string) error                                          // 清除标签关联的全部缓存
+}
+
+// OutputCache 输出缓存
+type OutputCache interface {
+	Policy(name string) (*OutputCachePolicy, bool) // 缓存策略, 名称为空时返回默认策略
+	MaxBodySize() int64                            // 可缓存的最大响应体字节数
+}
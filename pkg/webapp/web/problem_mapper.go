@@ -0,0 +1,11 @@
+package web
+
+import "github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+
+// ProblemMapper 错误映射, 将错误转换为 Problem Details 错误
+type ProblemMapper interface {
+	Map(err error) *problem.Error
+}
+
+// ContextExceptionHandlerKey 异常处理中间件在请求上下文中的键, 存在时错误由其渲染为 Problem Details
+const ContextExceptionHandlerKey = "exceptionHandler"
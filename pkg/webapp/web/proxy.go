@@ -0,0 +1,22 @@
+package web
+
+import "github.com/gin-gonic/gin"
+
+// ProxyPathParam 代理路由通配参数名, 为路由前缀之后的请求路径
+const ProxyPathParam = "proxyPath"
+
+// ProxyTransform 代理请求与响应的转换
+type ProxyTransform struct {
+	StripPrefix           bool              `mapstructure:"strip_prefix"`            // 去除路由前缀, 仅转发前缀之后的路径
+	AddPrefix             string            `mapstructure:"add_prefix"`              // 转发路径追加的前缀
+	PreserveHost          bool              `mapstructure:"preserve_host"`           // 保留原始 Host, 默认使用目标地址的 Host
+	RequestHeaders        map[string]string `mapstructure:"request_headers"`         // 设置的请求头
+	RemoveRequestHeaders  []string          `mapstructure:"remove_request_headers"`  // 移除的请求头
+	ResponseHeaders       map[string]string `mapstructure:"response_headers"`        // 设置的响应头
+	RemoveResponseHeaders []string          `mapstructure:"remove_response_headers"` // 移除的响应头
+}
+
+// Proxy 反向代理
+type Proxy interface {
+	Forward(cluster string, transform ProxyTransform) (gin.HandlerFunc, error) // 返回将请求转发到上游集群的处理函数, 集群不存在时返回错误
+}
@@ -2,8 +2,18 @@ package web
 
 import "time"
 
+// RateLimitDisabled 禁用限流的保留策略名称, 路由声明该策略后全局及路由限流器均不生效
+const RateLimitDisabled = "__disable_rate_limiting__"
+
 // RateLimiter 限流器接口
 type RateLimiter interface {
 	TryAcquire(key string) (bool, time.Duration) // TryAcquire 尝试获取访问权限
 	Release(key string)                          // Release 释放资源(用于并发限流)
 }
+
+// RefundableLimiter 可归还许可的限流器, 链式限流中后续限流器拒绝时用于回滚已获取的许可
+// 未实现该接口的限流器回滚时调用 Release
+type RefundableLimiter interface {
+	RateLimiter
+	Refund(key string) // Refund 归还已获取的许可
+}
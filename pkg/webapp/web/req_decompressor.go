@@ -3,4 +3,7 @@ package web
 // Decompressor 请求解压管理者
 type ReqDecompressor interface {
 	Decompression(tName string) (ReqDecompression, bool)
+	MaxDecompressedSize() int64      // 解压后请求体最大字节数, 0 表示不限制
+	MaxRatio() float64               // 最大压缩比, 0 表示不限制
+	LimitsDisabled(path string) bool // 路由是否不受解压限制
 }
@@ -0,0 +1,18 @@
+package web
+
+import "io"
+
+// CompressionLevel 压缩级别
+type CompressionLevel int
+
+const (
+	CompressionDefault CompressionLevel = iota // 默认级别, 兼顾速度与压缩率
+	CompressionFastest                         // 最快速度
+	CompressionOptimal                         // 最高压缩率
+)
+
+// RespCompression 响应压缩接口
+type RespCompression interface {
+	Type() string
+	Compression(w io.Writer, level CompressionLevel) (io.WriteCloser, error)
+}
@@ -0,0 +1,9 @@
+package web
+
+// RespCompressor 响应压缩管理者
+type RespCompressor interface {
+	Negotiate(acceptEncoding string) (RespCompression, bool) // 根据 Accept-Encoding 选择压缩提供者
+	Compressible(contentType string) bool                    // 响应类型是否允许压缩
+	MinSize() int                                            // 响应体达到该大小才进行压缩
+	Level() CompressionLevel                                 // 压缩级别
+}
@@ -1,5 +1,10 @@
 package web
 
+import (
+	"reflect"
+	"time"
+)
+
 // RouteConfig 路由配置
 type RouteConfig struct {
 	Path           string
@@ -9,6 +14,40 @@ type RouteConfig struct {
 	Schemes        []string
 	Policies       []string
 	RateLimiter    []string
+	Timeout        time.Duration // 路由超时, 覆盖 server 的读写超时, 到期后取消请求上下文
+
+	// 文档元数据, 用于生成 OpenAPI 文档
+	Name                    string         // 操作 ID
+	Summary                 string         // 摘要
+	Description             string         // 描述
+	Tags                    []string       // 标签
+	GroupName               string         // 所属文档, 为空时出现在全部文档中
+	Request                 reflect.Type   // 请求模型
+	Responses               []ResponseType // 响应模型
+	ExcludedFromDescription bool           // 不出现在文档中
+
+	// API 版本, 均为空时路由与版本无关
+	ApiVersions           []string // 支持的版本
+	DeprecatedApiVersions []string // 已弃用但仍支持的版本
+
+	OutputCache       bool   // 是否缓存响应
+	OutputCachePolicy string // 输出缓存策略, 为空时使用默认策略
+
+	// HTTP 缓存
+	CacheControl *CacheControl // Cache-Control 策略, 成功响应且处理函数未设置时写出
+	ETag         ETagKind      // 按响应体生成 ETag 并处理条件请求
+
+	Idempotent          bool // 按幂等键保存并重放响应
+	AntiforgeryDisabled bool // 不校验防伪令牌, 用于 webhook 等不经由浏览器的接口
+
+	CorsPolicy string // 跨域策略, 为空时使用默认策略
+}
+
+// ResponseType 响应模型
+type ResponseType struct {
+	Status      int          // 状态码
+	Type        reflect.Type // 响应模型, 为空时无响应体
+	ContentType string       // 响应类型, 为空时为 application/json
 }
 
 // WithAuthenticationScheme 配置认证方案
@@ -29,8 +68,143 @@ func (config *RouteConfig) WithRateLimiter(limiters ...string) *RouteConfig {
 	return config
 }
 
+// DisableRateLimiting 禁用限流, 全局限流器与路由限流器均不再生效
+func (config *RouteConfig) DisableRateLimiting() *RouteConfig {
+	config.RateLimiter = []string{RateLimitDisabled}
+	return config
+}
+
 // WithAllowAnonymous 配置允许匿名访问
 func (config *RouteConfig) WithAllowAnonymous() *RouteConfig {
 	config.AllowAnonymous = true
 	return config
 }
+
+// WithTimeout 配置路由超时
+// 超时后请求上下文被取消, 同时将连接的读写截止时间延长至该超时, 适用于文件上传、SSE 等长耗时路由
+func (config *RouteConfig) WithTimeout(timeout time.Duration) *RouteConfig {
+	config.Timeout = timeout
+	return config
+}
+
+// WithName 配置操作 ID
+func (config *RouteConfig) WithName(name string) *RouteConfig {
+	config.Name = name
+	return config
+}
+
+// WithSummary 配置摘要
+func (config *RouteConfig) WithSummary(summary string) *RouteConfig {
+	config.Summary = summary
+	return config
+}
+
+// WithDescription 配置描述
+func (config *RouteConfig) WithDescription(description string) *RouteConfig {
+	config.Description = description
+	return config
+}
+
+// WithTags 配置标签
+func (config *RouteConfig) WithTags(tags ...string) *RouteConfig {
+	config.Tags = append(config.Tags, tags...)
+	return config
+}
+
+// WithGroupName 配置所属文档
+func (config *RouteConfig) WithGroupName(name string) *RouteConfig {
+	config.GroupName = name
+	return config
+}
+
+// Accepts 配置请求模型, 字段按 path、query、header、json 标签生成参数与请求体
+func (config *RouteConfig) Accepts(model any) *RouteConfig {
+	config.Request = modelType(model)
+	return config
+}
+
+// Produces 配置响应模型, model 为 nil 时表示无响应体
+func (config *RouteConfig) Produces(status int, model any, contentType ...string) *RouteConfig {
+	response := ResponseType{Status: status, Type: modelType(model)}
+	if len(contentType) > 0 {
+		response.ContentType = contentType[0]
+	}
+	config.Responses = append(config.Responses, response)
+	return config
+}
+
+// ExcludeFromDescription 不在文档中描述该路由
+func (config *RouteConfig) ExcludeFromDescription() *RouteConfig {
+	config.ExcludedFromDescription = true
+	return config
+}
+
+// WithApiVersions 配置支持的 API 版本
+func (config *RouteConfig) WithApiVersions(versions ...string) *RouteConfig {
+	config.ApiVersions = append(config.ApiVersions, versions...)
+	return config
+}
+
+// WithDeprecatedApiVersions 配置已弃用但仍支持的 API 版本, 请求这些版本时输出 Deprecation 头
+func (config *RouteConfig) WithDeprecatedApiVersions(versions ...string) *RouteConfig {
+	config.DeprecatedApiVersions = append(config.DeprecatedApiVersions, versions...)
+	return config
+}
+
+// WithOutputCache 缓存 GET 请求的响应, 不指定策略时使用默认策略
+func (config *RouteConfig) WithOutputCache(policy ...string) *RouteConfig {
+	config.OutputCache = true
+	if len(policy) > 0 {
+		config.OutputCachePolicy = policy[0]
+	}
+	return config
+}
+
+// WithCacheControl 配置成功响应的 Cache-Control
+func (config *RouteConfig) WithCacheControl(cacheControl CacheControl) *RouteConfig {
+	config.CacheControl = &cacheControl
+	return config
+}
+
+// WithETag 按响应体生成 ETag, GET 请求的 If-None-Match 匹配时返回 304; 默认生成强 ETag
+func (config *RouteConfig) WithETag(kind ...ETagKind) *RouteConfig {
+	config.ETag = ETagStrong
+	if len(kind) > 0 {
+		config.ETag = kind[0]
+	}
+	return config
+}
+
+// WithIdempotency 按幂等键保存响应, 相同幂等键的重试请求重放首次的响应, 用于支付、下单等非幂等接口
+func (config *RouteConfig) WithIdempotency() *RouteConfig {
+	config.Idempotent = true
+	return config
+}
+
+// DisableAntiforgery 不校验防伪令牌
+func (config *RouteConfig) DisableAntiforgery() *RouteConfig {
+	config.AntiforgeryDisabled = true
+	return config
+}
+
+// WithCors 配置跨域策略
+func (config *RouteConfig) WithCors(policy string) *RouteConfig {
+	config.CorsPolicy = policy
+	return config
+}
+
+// Versioned 路由是否声明了 API 版本
+func (config *RouteConfig) Versioned() bool {
+	return len(config.ApiVersions)+len(config.DeprecatedApiVersions) > 0
+}
+
+// modelType 返回模型类型, 传入 reflect.Type 时直接使用
+func modelType(model any) reflect.Type {
+	if model == nil {
+		return nil
+	}
+	if t, ok := model.(reflect.Type); ok {
+		return t
+	}
+	return reflect.TypeOf(model)
+}
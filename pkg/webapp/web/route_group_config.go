@@ -1,13 +1,61 @@
 package web
 
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
 // GroupRouteConfig 分组路由配置
+// 分组的鉴权方案、授权策略、限流器与匿名访问由子分组与路由继承, 在生成路由表时合并, 因此可以在注册路由之后配置
 type GroupRouteConfig struct {
 	Prefix         string
 	Schemes        []string
 	Policies       []string
 	RateLimiter    []string
 	Routes         []*RouteConfig
+	Groups         []*GroupRouteConfig
 	AllowAnonymous bool
+	Timeout        time.Duration
+
+	// 文档元数据, 由子分组与路由继承
+	Tags                    []string
+	GroupName               string
+	ExcludedFromDescription bool
+
+	// API 版本, 路由未声明时使用最近分组声明的版本
+	ApiVersions           []string
+	DeprecatedApiVersions []string
+
+	// 输出缓存, 路由未配置时继承
+	OutputCache       bool
+	OutputCachePolicy string
+
+	// HTTP 缓存, 路由未配置时继承
+	CacheControl *CacheControl
+	ETag         ETagKind
+
+	// 幂等性与防伪校验, 由路由继承
+	Idempotent          bool
+	AntiforgeryDisabled bool
+
+	// 跨域策略, 路由未配置时继承
+	CorsPolicy string
+
+	parent *GroupRouteConfig
+}
+
+// MapGroup 创建子分组, 路径前缀与元数据继承自当前分组
+func (group *GroupRouteConfig) MapGroup(prefix string) *GroupRouteConfig {
+	child := &GroupRouteConfig{
+		Prefix: prefix,
+		parent: group,
+	}
+	group.Groups = append(group.Groups, child)
+	return child
 }
 
 // MapGet 注册GET请求路由
@@ -35,7 +83,32 @@ func (group *GroupRouteConfig) MapPatch(path string, handler any) *RouteConfig {
 	return group.mapRoute(path, PATCH, handler)
 }
 
-// registerPattern 注册路由模式到 httprouter
+// Map 注册指定请求方法的路由
+func (group *GroupRouteConfig) Map(method RequestMethod, path string, handler any) *RouteConfig {
+	return group.mapRoute(path, method, handler)
+}
+
+// proxyMethods 代理路由转发的请求方法
+var proxyMethods = []RequestMethod{GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS}
+
+// MapProxy 将分组下的全部请求转发到上游集群, 需先通过 AddReverseProxy 配置集群
+// 分组的鉴权方案、授权策略与限流器同样作用于代理路由, 代理路由不出现在文档中
+func (group *GroupRouteConfig) MapProxy(cluster string, transform ...ProxyTransform) *GroupRouteConfig {
+	var t ProxyTransform
+	if len(transform) > 0 {
+		t = transform[0]
+	}
+
+	handler := func(proxy Proxy) (gin.HandlerFunc, error) {
+		return proxy.Forward(cluster, t)
+	}
+	for _, method := range proxyMethods {
+		group.mapRoute("/*"+ProxyPathParam, method, handler).ExcludeFromDescription()
+	}
+	return group
+}
+
+// WithAuthenticationScheme 配置认证方案
 func (group *GroupRouteConfig) WithAuthenticationScheme(schemes ...string) *GroupRouteConfig {
 	group.Schemes = append(group.Schemes, schemes...)
 	return group
@@ -53,22 +126,205 @@ func (group *GroupRouteConfig) WithRateLimiter(limiters ...string) *GroupRouteCo
 	return group
 }
 
+// DisableRateLimiting 禁用分组下所有路由的限流
+func (group *GroupRouteConfig) DisableRateLimiting() *GroupRouteConfig {
+	group.RateLimiter = []string{RateLimitDisabled}
+	return group
+}
+
 // WithAllowAnonymous 配置允许匿名访问
 func (group *GroupRouteConfig) WithAllowAnonymous() *GroupRouteConfig {
 	group.AllowAnonymous = true
 	return group
 }
 
+// WithTimeout 配置分组下所有路由的超时
+func (group *GroupRouteConfig) WithTimeout(timeout time.Duration) *GroupRouteConfig {
+	group.Timeout = timeout
+	return group
+}
+
+// WithTags 配置分组下所有路由的文档标签
+func (group *GroupRouteConfig) WithTags(tags ...string) *GroupRouteConfig {
+	group.Tags = append(group.Tags, tags...)
+	return group
+}
+
+// WithGroupName 配置分组下所有路由所属的文档
+func (group *GroupRouteConfig) WithGroupName(name string) *GroupRouteConfig {
+	group.GroupName = name
+	return group
+}
+
+// ExcludeFromDescription 不在文档中描述分组下的路由
+func (group *GroupRouteConfig) ExcludeFromDescription() *GroupRouteConfig {
+	group.ExcludedFromDescription = true
+	return group
+}
+
+// WithApiVersions 配置分组下路由支持的 API 版本
+func (group *GroupRouteConfig) WithApiVersions(versions ...string) *GroupRouteConfig {
+	group.ApiVersions = append(group.ApiVersions, versions...)
+	return group
+}
+
+// WithDeprecatedApiVersions 配置分组下路由已弃用但仍支持的 API 版本
+func (group *GroupRouteConfig) WithDeprecatedApiVersions(versions ...string) *GroupRouteConfig {
+	group.DeprecatedApiVersions = append(group.DeprecatedApiVersions, versions...)
+	return group
+}
+
+// WithOutputCache 缓存分组下 GET 请求的响应, 不指定策略时使用默认策略
+func (group *GroupRouteConfig) WithOutputCache(policy ...string) *GroupRouteConfig {
+	group.OutputCache = true
+	if len(policy) > 0 {
+		group.OutputCachePolicy = policy[0]
+	}
+	return group
+}
+
+// WithCacheControl 配置分组下成功响应的 Cache-Control
+func (group *GroupRouteConfig) WithCacheControl(cacheControl CacheControl) *GroupRouteConfig {
+	group.CacheControl = &cacheControl
+	return group
+}
+
+// WithETag 按响应体为分组下的路由生成 ETag, 默认生成强 ETag
+func (group *GroupRouteConfig) WithETag(kind ...ETagKind) *GroupRouteConfig {
+	group.ETag = ETagStrong
+	if len(kind) > 0 {
+		group.ETag = kind[0]
+	}
+	return group
+}
+
+// WithIdempotency 按幂等键保存分组下路由的响应
+func (group *GroupRouteConfig) WithIdempotency() *GroupRouteConfig {
+	group.Idempotent = true
+	return group
+}
+
+// DisableAntiforgery 分组下的路由不校验防伪令牌
+func (group *GroupRouteConfig) DisableAntiforgery() *GroupRouteConfig {
+	group.AntiforgeryDisabled = true
+	return group
+}
+
+// WithCors 配置分组下路由的跨域策略
+func (group *GroupRouteConfig) WithCors(policy string) *GroupRouteConfig {
+	group.CorsPolicy = policy
+	return group
+}
+
 // mapRoute 注册路由
+// handler 可以是 gin.HandlerFunc, 也可以是返回 gin.HandlerFunc 的构造函数, 构造函数的参数由容器注入
 func (group *GroupRouteConfig) mapRoute(path string, method RequestMethod, handler any) *RouteConfig {
 	config := &RouteConfig{
-		Path:        path,
-		Method:      method,
-		Handler:     handler,
-		Schemes:     group.Schemes,
-		Policies:    group.Policies,
-		RateLimiter: group.RateLimiter,
+		Path:    path,
+		Method:  method,
+		Handler: handler,
 	}
 	group.Routes = append(group.Routes, config)
 	return config
 }
+
+// Table 生成路由表, 返回的路由为完整路径且已合并各级分组元数据的副本
+func (group *GroupRouteConfig) Table() []*RouteConfig {
+	var routes []*RouteConfig
+	group.collect(&routes)
+	return routes
+}
+
+func (group *GroupRouteConfig) collect(routes *[]*RouteConfig) {
+	for _, route := range group.Routes {
+		*routes = append(*routes, group.resolve(route))
+	}
+	for _, child := range group.Groups {
+		child.collect(routes)
+	}
+}
+
+// resolve 合并分组链与路由的元数据, 切片均重新分配, 不与分组共享底层数组
+func (group *GroupRouteConfig) resolve(route *RouteConfig) *RouteConfig {
+	resolved := *route
+	resolved.Schemes = nil
+	resolved.Policies = nil
+	resolved.RateLimiter = nil
+	resolved.Tags = nil
+	resolved.Responses = slices.Clone(route.Responses)
+	resolved.ApiVersions = slices.Clone(route.ApiVersions)
+	resolved.DeprecatedApiVersions = slices.Clone(route.DeprecatedApiVersions)
+
+	var chain []*GroupRouteConfig
+	for g := group; g != nil; g = g.parent {
+		chain = append(chain, g)
+	}
+	slices.Reverse(chain)
+
+	prefix := "/"
+	for _, g := range chain {
+		prefix = joinPaths(prefix, g.Prefix)
+		resolved.Schemes = appendUnique(resolved.Schemes, g.Schemes...)
+		resolved.Policies = appendUnique(resolved.Policies, g.Policies...)
+		resolved.RateLimiter = appendUnique(resolved.RateLimiter, g.RateLimiter...)
+		resolved.AllowAnonymous = resolved.AllowAnonymous || g.AllowAnonymous
+		if g.Timeout > 0 && route.Timeout <= 0 {
+			resolved.Timeout = g.Timeout
+		}
+		resolved.Tags = appendUnique(resolved.Tags, g.Tags...)
+		resolved.ExcludedFromDescription = resolved.ExcludedFromDescription || g.ExcludedFromDescription
+		if g.GroupName != "" && route.GroupName == "" {
+			resolved.GroupName = g.GroupName
+		}
+		if (len(g.ApiVersions) > 0 || len(g.DeprecatedApiVersions) > 0) && !route.Versioned() {
+			resolved.ApiVersions = slices.Clone(g.ApiVersions)
+			resolved.DeprecatedApiVersions = slices.Clone(g.DeprecatedApiVersions)
+		}
+		if g.OutputCache && !route.OutputCache {
+			resolved.OutputCache = true
+			resolved.OutputCachePolicy = g.OutputCachePolicy
+		}
+		if g.CacheControl != nil && route.CacheControl == nil {
+			resolved.CacheControl = g.CacheControl
+		}
+		if g.ETag != ETagNone && route.ETag == ETagNone {
+			resolved.ETag = g.ETag
+		}
+		resolved.Idempotent = resolved.Idempotent || g.Idempotent
+		resolved.AntiforgeryDisabled = resolved.AntiforgeryDisabled || g.AntiforgeryDisabled
+		if g.CorsPolicy != "" && route.CorsPolicy == "" {
+			resolved.CorsPolicy = g.CorsPolicy
+		}
+	}
+
+	resolved.Path = joinPaths(prefix, route.Path)
+	resolved.Schemes = appendUnique(resolved.Schemes, route.Schemes...)
+	resolved.Policies = appendUnique(resolved.Policies, route.Policies...)
+	resolved.RateLimiter = appendUnique(resolved.RateLimiter, route.RateLimiter...)
+	resolved.Tags = appendUnique(resolved.Tags, route.Tags...)
+
+	return &resolved
+}
+
+// joinPaths 拼接路径, 保留相对路径末尾的斜杠
+func joinPaths(absolute, relative string) string {
+	if relative == "" {
+		return absolute
+	}
+
+	joined := path.Join(absolute, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
+
+// appendUnique 追加不重复的元素
+func appendUnique(dst []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(dst, value) {
+			dst = append(dst, value)
+		}
+	}
+	return dst
+}
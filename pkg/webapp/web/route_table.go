@@ -0,0 +1,6 @@
+package web
+
+// RouteTable 路由表, 应用启动后包含全部已注册的路由, 用于诊断与 OpenAPI 文档生成
+type RouteTable interface {
+	Routes() []*RouteConfig
+}
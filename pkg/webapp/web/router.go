@@ -1,6 +1,10 @@
 package web
 
-import "github.com/gobwas/glob"
+import (
+	"context"
+
+	"github.com/gobwas/glob"
+)
 
 // RequestMethod is a type for HTTP request method
 type RequestMethod string
@@ -29,4 +33,11 @@ type Router interface {
 	Authenticate(scheme string) (Authenticate, bool)                    // 鉴权处理
 	Authorize(policy string) (func(claims *ClaimsPrincipal) bool, bool) // 授权处理
 	RateLimiter(policy string) (RateLimiter, bool)                      // 限流处理器
+	RateLimiterGroup(group string) ([]string, bool)                     // 限流端点组
+	GrpcSchemes(fullMethod string) []string                             // gRPC 方法鉴权方案
+	GrpcAllowAnonymous(fullMethod string) bool                          // gRPC 方法允许匿名访问
+	GrpcPolicies(fullMethod string) []string                            // gRPC 方法授权策略
+	GrpcRateLimit(fullMethod string) []string                           // gRPC 方法限流方案
+	GrpcPartitionKey(ctx context.Context) string                        // gRPC 限流分区键
+	GrpcMethodRules() GrpcMethodRules                                   // 配置的全部 gRPC 方法规则
 }
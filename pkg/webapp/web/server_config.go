@@ -1,9 +1,23 @@
 package web
 
+import "time"
+
 type ServerConfig struct {
-	HttpPort          string
-	GrpcPort          string
-	Environment       string
-	UseDefaultRecover bool
-	UseDefaultLogger  bool
+	HttpPort           string
+	GrpcPort           string
+	Environment        string
+	UseDefaultRecover  bool
+	UseDefaultLogger   bool
+	Urls               []string      // 监听地址, 支持 http://、https:// 与 unix://, 未配置时监听 HttpPort
+	SinglePort         bool          // HTTP 与 gRPC 共用 HttpPort, 按 content-type 分发
+	TLSCertFile        string        // TLS 证书, 设置后以 HTTPS 提供服务, 并通过 ALPN 协商 HTTP/2
+	TLSKeyFile         string        // TLS 私钥
+	ReadTimeout        time.Duration // 读取整个请求(含请求体)的超时, 0 表示不限制
+	ReadHeaderTimeout  time.Duration // 读取请求头的超时, 0 表示使用 ReadTimeout
+	WriteTimeout       time.Duration // 写响应的超时, 0 表示不限制
+	IdleTimeout        time.Duration // keep-alive 空闲连接超时
+	MaxHeaderBytes     int           // 请求头最大字节数, 0 表示使用默认值 1MB
+	MaxRequestBodySize int64         // 请求体最大字节数, 0 表示不限制, 不作用于 gRPC 请求
+	ShutdownTimeout    time.Duration // 优雅停机等待时间
+	DrainDelay         time.Duration // 停机前将就绪状态置为 false 并等待的时间, 便于负载均衡摘除流量
 }
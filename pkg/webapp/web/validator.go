@@ -0,0 +1,102 @@
+package web
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"golang.org/x/text/language"
+)
+
+var (
+	validatorOnce sync.Once
+	validate      *validator.Validate
+	translators   *ut.UniversalTranslator
+)
+
+// Validator 返回 Handle 使用的校验器, 可用于注册自定义校验规则
+// 校验规则使用 validate 标签, 错误信息中的字段名取自 json、path、query、header、form 标签
+func Validator() *validator.Validate {
+	validatorOnce.Do(func() {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.RegisterTagNameFunc(fieldName)
+
+		english, chinese := en.New(), zh.New()
+		translators = ut.New(english, english, chinese)
+
+		enTrans, _ := translators.GetTranslator("en")
+		_ = enTranslations.RegisterDefaultTranslations(validate, enTrans)
+
+		zhTrans, _ := translators.GetTranslator("zh")
+		_ = zhTranslations.RegisterDefaultTranslations(validate, zhTrans)
+	})
+	return validate
+}
+
+// ValidateStruct 校验结构体, 失败时返回按 acceptLanguage 翻译的 problem.Validation 错误
+func ValidateStruct(obj any, acceptLanguage string) error {
+	err := Validator().Struct(obj)
+	if err == nil {
+		return nil
+	}
+
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return problem.BadRequest(err.Error()).WithError(err)
+	}
+
+	trans := translator(acceptLanguage)
+	fields := make([]problem.FieldError, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, problem.FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Message: fe.Translate(trans),
+			Code:    fe.Tag(),
+		})
+	}
+
+	return problem.Validation(fields...).WithError(err)
+}
+
+// translator 按 Accept-Language 选择翻译器, 不支持的语言使用英文
+func translator(acceptLanguage string) ut.Translator {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		locales = append(locales, base.String())
+	}
+
+	trans, _ := translators.FindTranslator(locales...)
+	return trans
+}
+
+// fieldName 字段在请求中的名称
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "path", "query", "header", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldPath 去掉命名空间中的顶层结构体名, 如 CreateUserReq.address.city 转为 address.city
+func fieldPath(namespace string) string {
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocket 消息类型, 与 RFC 6455 的帧类型一致
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+var (
+	// ErrWebSocketBackpressure 发送队列已满, 客户端读取过慢
+	ErrWebSocketBackpressure = errors.New("websocket send buffer is full")
+	// ErrWebSocketClosed 连接已关闭
+	ErrWebSocketClosed = errors.New("websocket connection is closed")
+)
+
+// WebSocketConn WebSocket 连接
+// 读操作只能由处理函数所在的协程调用; 写操作可并发调用, 消息进入发送队列后由写协程依次写出
+type WebSocketConn interface {
+	ID() string                  // 连接标识
+	Context() context.Context    // 连接关闭或应用停止时取消, 携带握手请求上下文中的值
+	Request() *http.Request      // 握手请求
+	Param(name string) string    // 握手请求的路由参数
+	Principal() *ClaimsPrincipal // 握手时认证的用户, 匿名访问时为 nil
+	ReadMessage() (messageType int, data []byte, err error)
+	ReadJSON(v any) error
+	WriteMessage(messageType int, data []byte) error // 加入发送队列, 队列已满时返回 ErrWebSocketBackpressure
+	WriteJSON(v any) error
+	Close(code int, reason string) error // 写出关闭帧并关闭连接
+}
+
+// WebSocketHandler WebSocket 处理函数, 返回时关闭连接; 返回 nil 时以正常关闭码关闭, 否则以内部错误关闭
+type WebSocketHandler func(conn WebSocketConn) error
+
+// WebSocketServer 升级 WebSocket 连接并管理连接的生命周期, 应用停止时关闭全部连接
+type WebSocketServer interface {
+	Handler(handler WebSocketHandler) gin.HandlerFunc // 返回升级连接并调用 handler 的处理函数
+	Connections() int                                 // 当前连接数
+}
+
+// WebSocketHub 按分组广播消息, 连接关闭时自动离开全部分组
+type WebSocketHub interface {
+	Join(group string, conn WebSocketConn)
+	Leave(group string, conn WebSocketConn)
+	Broadcast(ctx context.Context, group string, v any) error // 以 JSON 文本消息广播到分组内的全部连接
+}
@@ -4,25 +4,39 @@ import (
 	"fmt"
 
 	"github.com/xiaohangshu-dev/go-workit/pkg/app"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/antiforgery"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/auth"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/authz"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/cors"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/dbctx"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/elasticctx"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/esctx"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/gormctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/grpcctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/health"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/idempotency"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/kafkactx"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/minioctx"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/mongoctx"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/openapi"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/outputcache"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/proxy"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/redisctx"
 
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ginx"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/localiza"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/reqdecp"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/respcomp"
 
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/ratelimit"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/router"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/rpc"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/versioning"
 	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/websocket"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
 // WebApplicationBuilder 构建web应用
@@ -34,6 +48,18 @@ type WebApplicationBuilder struct {
 	localizaOpts  *localiza.Options
 	rateLimitOpts *ratelimit.Options
 	reqdecpOpts   *reqdecp.Options
+	respcompOpts  *respcomp.Options
+	grpcOpts      *rpc.GrpcOptions
+	healthOpts    *health.Options
+	problemOpts   *problem.Options
+	openApiOpts   *openapi.Options
+	versionOpts   *versioning.Options
+	outputOpts    *outputcache.Options
+	idemOpts      *idempotency.Options
+	antiOpts      *antiforgery.Options
+	corsOpts      *cors.Options
+	proxyOpts     *proxy.Options
+	wsOpts        *websocket.Options
 	router        *router.Router
 }
 
@@ -171,6 +197,18 @@ func (b *WebApplicationBuilder) AddEsContext(fn func(options *esctx.Options)) *W
 	return b
 }
 
+// AddGrpcClients 添加 gRPC 客户端
+func (b *WebApplicationBuilder) AddGrpcClients(fn func(options *grpcctx.Options)) *WebApplicationBuilder {
+
+	opts := grpcctx.NewOptions()
+
+	fn(opts)
+
+	b.ApplicationBuilder.AddServices(opts.Container()...)
+
+	return b
+}
+
 // AddLocalization 添加国际化配置
 func (b *WebApplicationBuilder) AddLocalization(fn func(options *localiza.Options)) *WebApplicationBuilder {
 	opts := localiza.NewOptions()
@@ -197,6 +235,130 @@ func (b *WebApplicationBuilder) AddRequestDecompression(fn ...func(options *reqd
 	return b
 }
 
+// AddResponseCompression 添加响应压缩配置
+func (b *WebApplicationBuilder) AddResponseCompression(fn ...func(options *respcomp.Options)) *WebApplicationBuilder {
+	opts := respcomp.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.respcompOpts = opts
+	return b
+}
+
+// AddProblemDetails 添加 Problem Details 错误映射配置
+func (b *WebApplicationBuilder) AddProblemDetails(fn ...func(options *problem.Options)) *WebApplicationBuilder {
+	opts := problem.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.problemOpts = opts
+	return b
+}
+
+// AddOpenApi 添加 OpenAPI 文档配置, 文档由路由表生成, 通过 UseOpenApi 提供
+func (b *WebApplicationBuilder) AddOpenApi(fn ...func(options *openapi.Options)) *WebApplicationBuilder {
+	opts := openapi.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.openApiOpts = opts
+	return b
+}
+
+// AddApiVersioning 添加 API 版本配置
+func (b *WebApplicationBuilder) AddApiVersioning(fn ...func(options *versioning.Options)) *WebApplicationBuilder {
+	opts := versioning.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.versionOpts = opts
+	return b
+}
+
+// AddOutputCache 添加输出缓存配置
+func (b *WebApplicationBuilder) AddOutputCache(fn ...func(options *outputcache.Options)) *WebApplicationBuilder {
+	opts := outputcache.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.outputOpts = opts
+	return b
+}
+
+// AddIdempotency 添加幂等性配置
+func (b *WebApplicationBuilder) AddIdempotency(fn ...func(options *idempotency.Options)) *WebApplicationBuilder {
+	opts := idempotency.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.idemOpts = opts
+	return b
+}
+
+// AddAntiforgery 添加防伪令牌配置
+func (b *WebApplicationBuilder) AddAntiforgery(fn ...func(options *antiforgery.Options)) *WebApplicationBuilder {
+	opts := antiforgery.NewOptions()
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.antiOpts = opts
+	return b
+}
+
+// AddCors 添加跨域配置, 先从配置 cors.default_policy 与 cors.policies 加载策略, 再由 fn 添加或覆盖
+func (b *WebApplicationBuilder) AddCors(fn ...func(options *cors.Options)) *WebApplicationBuilder {
+	opts := cors.NewOptions()
+	if err := opts.LoadConfig(b.Config()); err != nil {
+		panic(err)
+	}
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.corsOpts = opts
+	return b
+}
+
+// AddReverseProxy 添加反向代理, 先从配置 proxy.clusters 与 proxy.routes 加载集群与代理路由, 再由 fn 添加或修改集群
+// 配置文件中的代理路由在构建应用时注册, 代码中可通过分组的 MapProxy 注册
+func (b *WebApplicationBuilder) AddReverseProxy(fn ...func(options *proxy.Options)) *WebApplicationBuilder {
+	opts := proxy.NewOptions()
+	if err := opts.LoadConfig(b.Config()); err != nil {
+		panic(err)
+	}
+	if len(fn) != 0 {
+		fn[0](opts)
+	}
+	b.proxyOpts = opts
+	return b
+}
+
+// AddWebSockets 配置 WebSocket, 未配置时使用默认配置与内存广播
+func (b *WebApplicationBuilder) AddWebSockets(fn ...func(options *websocket.Options)) *WebApplicationBuilder {
+	b.wsOpts = websocket.NewOptions()
+	if len(fn) != 0 {
+		fn[0](b.wsOpts)
+	}
+	return b
+}
+
+// ConfigureGrpc 配置 gRPC 服务
+func (b *WebApplicationBuilder) ConfigureGrpc(fn func(options *rpc.GrpcOptions)) *WebApplicationBuilder {
+	if b.grpcOpts == nil {
+		b.grpcOpts = rpc.NewGrpcOptions()
+	}
+	fn(b.grpcOpts)
+	return b
+}
+
+// AddHealthChecks 添加健康检查
+func (b *WebApplicationBuilder) AddHealthChecks(fn func(options *health.Options)) *WebApplicationBuilder {
+	if b.healthOpts == nil {
+		b.healthOpts = health.NewOptions()
+	}
+	fn(b.healthOpts)
+	return b
+}
+
 // Build 构建应用
 func (b *WebApplicationBuilder) Build(fn ...func(b *WebApplicationBuilder) web.Application) web.Application {
 	// 构建应用主机
@@ -215,6 +377,21 @@ func (b *WebApplicationBuilder) Build(fn ...func(b *WebApplicationBuilder) web.A
 	if b.reqdecpOpts == nil {
 		b.reqdecpOpts = reqdecp.NewOptions()
 	}
+	if b.respcompOpts == nil {
+		b.respcompOpts = respcomp.NewOptions()
+	}
+	if b.grpcOpts == nil {
+		b.grpcOpts = rpc.NewGrpcOptions()
+	}
+	if b.healthOpts == nil {
+		b.healthOpts = health.NewOptions()
+	}
+	if b.problemOpts == nil {
+		b.problemOpts = problem.NewOptions()
+	}
+	if b.wsOpts == nil {
+		b.wsOpts = websocket.NewOptions()
+	}
 
 	// 构建国际化
 	if b.localizaOpts != nil {
@@ -229,11 +406,32 @@ func (b *WebApplicationBuilder) Build(fn ...func(b *WebApplicationBuilder) web.A
 	}
 
 	// 构建请求解压
-	reqDecompressor := reqdecp.NewReqDecompressor(b.reqdecpOpts.Decompressions())
+	reqDecompressor := reqdecp.NewReqDecompressor(b.reqdecpOpts)
 	b.app.AppendContainer(fx.Provide(func() web.ReqDecompressor {
 		return reqDecompressor
 	}))
 
+	// 构建响应压缩
+	respCompressor := respcomp.NewRespCompressor(b.respcompOpts)
+	b.app.AppendContainer(fx.Provide(func() web.RespCompressor {
+		return respCompressor
+	}))
+
+	// 构建错误映射
+	problemMapper := problem.NewErrorMapper(b.problemOpts)
+	b.app.AppendContainer(fx.Provide(func() web.ProblemMapper {
+		return problemMapper
+	}))
+
+	// 构建健康检查
+	healthCheck := health.NewRegistry(b.healthOpts)
+	b.app.AppendContainer(fx.Provide(func() web.HealthCheck {
+		return healthCheck
+	}))
+
+	// 注册 gRPC 配置与拦截器
+	b.app.AppendContainer(b.grpcOpts.Container()...)
+
 	// 构建路由配置
 	b.router = router.NewRouter(b.authOpts, b.authzOpts, b.rateLimitOpts)
 
@@ -242,12 +440,105 @@ func (b *WebApplicationBuilder) Build(fn ...func(b *WebApplicationBuilder) web.A
 		return b.router
 	}))
 
+	// 构建 API 版本
+	if b.versionOpts != nil {
+		apiVersioning := versioning.NewApiVersioning(b.versionOpts)
+		b.app.AppendContainer(fx.Provide(func() web.ApiVersioning {
+			return apiVersioning
+		}))
+	}
+
+	// 构建输出缓存, 未配置存储时使用内存存储
+	if b.outputOpts != nil {
+		outputCache := outputcache.NewOutputCache(b.outputOpts)
+		b.app.AppendContainer(fx.Provide(func() web.OutputCache {
+			return outputCache
+		}))
+
+		if store := b.outputOpts.Store(); store != nil {
+			b.app.AppendContainer(fx.Provide(fx.Annotate(store, fx.As(new(web.OutputCacheStore)))))
+		} else {
+			b.app.AppendContainer(fx.Provide(func() web.OutputCacheStore {
+				return outputcache.NewMemoryStore()
+			}))
+		}
+	}
+
+	// 构建幂等性配置, 未配置存储时使用内存存储
+	if b.idemOpts != nil {
+		idem := idempotency.NewIdempotency(b.idemOpts)
+		b.app.AppendContainer(fx.Provide(func() web.Idempotency {
+			return idem
+		}))
+
+		if store := b.idemOpts.Store(); store != nil {
+			b.app.AppendContainer(fx.Provide(fx.Annotate(store, fx.As(new(web.IdempotencyStore)))))
+		} else {
+			b.app.AppendContainer(fx.Provide(func() web.IdempotencyStore {
+				return idempotency.NewMemoryStore()
+			}))
+		}
+	}
+
+	// 构建防伪令牌服务
+	if b.antiOpts != nil {
+		af := antiforgery.NewAntiforgery(b.antiOpts)
+		b.app.AppendContainer(fx.Provide(func() web.Antiforgery {
+			return af
+		}))
+	}
+
+	// 构建跨域策略, 策略配置无效时启动失败
+	if b.corsOpts != nil {
+		b.app.AppendContainer(fx.Provide(func() (web.Cors, error) {
+			return cors.NewCors(b.corsOpts)
+		}))
+
+		if validator := b.corsOpts.OriginValidator(); validator != nil {
+			b.app.AppendContainer(fx.Provide(fx.Annotate(validator, fx.As(new(web.CorsOriginValidator)))))
+		}
+	}
+
+	// 构建反向代理, 集群配置无效时启动失败
+	if b.proxyOpts != nil {
+		b.app.AppendContainer(fx.Provide(func(lc fx.Lifecycle, logger *zap.Logger) (web.Proxy, error) {
+			return proxy.NewProxy(b.proxyOpts, lc, logger)
+		}))
+	}
+
+	// 构建 WebSocket 服务与广播, 未配置广播时使用内存广播
+	b.app.AppendContainer(fx.Provide(func(lc fx.Lifecycle, logger *zap.Logger) web.WebSocketServer {
+		return websocket.NewServer(b.wsOpts, lc, logger)
+	}))
+	if hub := b.wsOpts.Hub(); hub != nil {
+		b.app.AppendContainer(fx.Provide(fx.Annotate(hub, fx.As(new(web.WebSocketHub)))))
+	} else {
+		b.app.AppendContainer(fx.Provide(func(logger *zap.Logger) web.WebSocketHub {
+			return websocket.NewMemoryHub(logger)
+		}))
+	}
+
+	// 构建 OpenAPI 文档, 路由表由应用提供
+	if b.openApiOpts != nil {
+		b.app.AppendContainer(fx.Provide(func(routes web.RouteTable, router web.Router) web.OpenApi {
+			return openapi.NewGenerator(b.openApiOpts, routes, router)
+		}))
+	}
+
 	// 构建应用
+	var application web.Application
 	if len(fn) > 0 {
-		return fn[0](b)
+		application = fn[0](b)
+	} else {
+		application = ginx.NewWebApplication(b.app)
+	}
+
+	// 注册配置文件中的代理路由
+	if b.proxyOpts != nil {
+		b.proxyOpts.MapRoutes(application)
 	}
 
-	return ginx.NewWebApplication(b.app)
+	return application
 }
 
 // App 获取应用实例
@@ -0,0 +1,222 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gws "github.com/gorilla/websocket"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+)
+
+// message 发送队列中的消息
+type message struct {
+	messageType int
+	data        []byte
+}
+
+// Conn WebSocket 连接, 写操作经发送队列由写协程写出, 写协程同时负责定期发送 ping
+type Conn struct {
+	id        string
+	ws        *gws.Conn
+	request   *http.Request
+	params    gin.Params
+	principal *web.ClaimsPrincipal
+	opts      *Options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	send       chan message
+	closing    chan []byte   // 关闭帧, 写协程写出队列中的消息后写出
+	writerDone chan struct{} // 写协程已退出
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+}
+
+// newConn 创建连接, 连接的 context 不随握手请求结束而取消, 保留其中的值
+func newConn(ws *gws.Conn, c *gin.Context, principal *web.ClaimsPrincipal, opts *Options) *Conn {
+	r := c.Request
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	conn := &Conn{
+		id:         newConnID(),
+		ws:         ws,
+		request:    r,
+		params:     slices.Clone(c.Params),
+		principal:  principal,
+		opts:       opts,
+		ctx:        ctx,
+		cancel:     cancel,
+		send:       make(chan message, opts.SendBufferSize),
+		closing:    make(chan []byte, 1),
+		writerDone: make(chan struct{}),
+	}
+
+	ws.SetReadLimit(opts.ReadLimit)
+	_ = ws.SetReadDeadline(time.Now().Add(opts.PongTimeout))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(opts.PongTimeout))
+	})
+	return conn
+}
+
+// ID 连接标识
+func (c *Conn) ID() string {
+	return c.id
+}
+
+// Context 连接的 context
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// Request 握手请求
+func (c *Conn) Request() *http.Request {
+	return c.request
+}
+
+// Param 握手请求的路由参数
+func (c *Conn) Param(name string) string {
+	return c.params.ByName(name)
+}
+
+// Principal 握手时认证的用户
+func (c *Conn) Principal() *web.ClaimsPrincipal {
+	return c.principal
+}
+
+// ReadMessage 读取消息, 收到任何消息都会延长读超时
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.ws.ReadMessage()
+	if err != nil {
+		return messageType, data, err
+	}
+	_ = c.ws.SetReadDeadline(time.Now().Add(c.opts.PongTimeout))
+	return messageType, data, nil
+}
+
+// ReadJSON 读取消息并按 JSON 解码
+func (c *Conn) ReadJSON(v any) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteMessage 将消息加入发送队列
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return web.ErrWebSocketClosed
+	}
+	select {
+	case c.send <- message{messageType: messageType, data: data}:
+		return nil
+	default:
+		return web.ErrWebSocketBackpressure
+	}
+}
+
+// WriteJSON 按 JSON 编码后以文本消息加入发送队列
+func (c *Conn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(web.TextMessage, data)
+}
+
+// Close 写出队列中的消息与关闭帧后关闭连接, 重复调用时不做处理
+func (c *Conn) Close(code int, reason string) error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+
+		c.closing <- gws.FormatCloseMessage(code, reason)
+		select {
+		case <-c.writerDone:
+		case <-time.After(c.opts.CloseTimeout):
+		}
+
+		c.cancel()
+		_ = c.ws.Close()
+	})
+	return nil
+}
+
+// writeLoop 写出发送队列中的消息并定期发送 ping, 写失败时关闭连接
+func (c *Conn) writeLoop() {
+	ticker := time.NewTicker(c.opts.PingInterval)
+	defer func() {
+		ticker.Stop()
+		close(c.writerDone)
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			if err := c.write(msg); err != nil {
+				c.abort()
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(gws.PingMessage, nil, time.Now().Add(c.opts.WriteTimeout)); err != nil {
+				c.abort()
+				return
+			}
+		case frame := <-c.closing:
+			c.drain()
+			_ = c.ws.WriteControl(gws.CloseMessage, frame, time.Now().Add(c.opts.WriteTimeout))
+			return
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// drain 写出关闭前已加入队列的消息
+func (c *Conn) drain() {
+	for {
+		select {
+		case msg := <-c.send:
+			if err := c.write(msg); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *Conn) write(msg message) error {
+	_ = c.ws.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout))
+	return c.ws.WriteMessage(msg.messageType, msg.data)
+}
+
+// abort 写失败时直接关闭底层连接, 处理函数的读操作随之返回错误
+func (c *Conn) abort() {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	c.cancel()
+	_ = c.ws.Close()
+}
+
+func newConnID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
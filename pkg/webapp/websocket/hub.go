@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	gws "github.com/gorilla/websocket"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/zap"
+)
+
+// MemoryHub 内存广播, 仅广播到当前实例的连接
+// 发送队列已满的连接视为读取过慢, 以 1013 关闭, 避免拖慢其他连接
+type MemoryHub struct {
+	mu      sync.RWMutex
+	groups  map[string]map[web.WebSocketConn]struct{}
+	members map[web.WebSocketConn]map[string]struct{}
+	logger  *zap.Logger
+}
+
+// NewMemoryHub 创建内存广播
+func NewMemoryHub(logger *zap.Logger) *MemoryHub {
+	return &MemoryHub{
+		groups:  make(map[string]map[web.WebSocketConn]struct{}),
+		members: make(map[web.WebSocketConn]map[string]struct{}),
+		logger:  logger,
+	}
+}
+
+// Join 加入分组, 连接首次加入分组时在其关闭后离开全部分组
+func (h *MemoryHub) Join(group string, conn web.WebSocketConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	joined, ok := h.members[conn]
+	if !ok {
+		joined = make(map[string]struct{})
+		h.members[conn] = joined
+		go func() {
+			<-conn.Context().Done()
+			h.leaveAll(conn)
+		}()
+	}
+	joined[group] = struct{}{}
+
+	if h.groups[group] == nil {
+		h.groups[group] = make(map[web.WebSocketConn]struct{})
+	}
+	h.groups[group][conn] = struct{}{}
+}
+
+// Leave 离开分组
+func (h *MemoryHub) Leave(group string, conn web.WebSocketConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leave(group, conn)
+}
+
+// Broadcast 以 JSON 文本消息广播到分组内的全部连接
+func (h *MemoryHub) Broadcast(ctx context.Context, group string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	h.deliver(group, data)
+	return nil
+}
+
+// deliver 将消息写入分组内全部连接的发送队列
+func (h *MemoryHub) deliver(group string, data []byte) {
+	h.mu.RLock()
+	conns := make([]web.WebSocketConn, 0, len(h.groups[group]))
+	for conn := range h.groups[group] {
+		conns = append(conns, conn)
+	}
+	h.mu.RUnlock()
+
+	for _, conn := range conns {
+		err := conn.WriteMessage(web.TextMessage, data)
+		if errors.Is(err, web.ErrWebSocketBackpressure) {
+			h.logger.Warn("closing slow websocket consumer", zap.String("group", group), zap.String("conn", conn.ID()))
+			go func(conn web.WebSocketConn) {
+				_ = conn.Close(gws.CloseTryAgainLater, "send buffer is full")
+			}(conn)
+		}
+	}
+}
+
+func (h *MemoryHub) leaveAll(conn web.WebSocketConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for group := range h.members[conn] {
+		h.leave(group, conn)
+	}
+	delete(h.members, conn)
+}
+
+func (h *MemoryHub) leave(group string, conn web.WebSocketConn) {
+	if conns, ok := h.groups[group]; ok {
+		delete(conns, conn)
+		if len(conns) == 0 {
+			delete(h.groups, group)
+		}
+	}
+	if joined, ok := h.members[conn]; ok {
+		delete(joined, group)
+	}
+}
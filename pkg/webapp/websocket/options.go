@@ -0,0 +1,46 @@
+package websocket
+
+import (
+	"net/http"
+	"time"
+)
+
+// Options WebSocket 配置
+type Options struct {
+	ReadLimit         int64                      // 单条消息的最大字节数, 超过时以 1009 关闭连接
+	SendBufferSize    int                        // 每个连接的发送队列长度, 队列已满时写入返回 ErrWebSocketBackpressure
+	WriteTimeout      time.Duration              // 单条消息的写超时
+	PingInterval      time.Duration              // 发送 ping 的间隔
+	PongTimeout       time.Duration              // 等待 pong 或其他消息的超时, 须大于 PingInterval
+	HandshakeTimeout  time.Duration              // 握手超时
+	CloseTimeout      time.Duration              // 关闭时写出队列中消息与关闭帧的超时
+	EnableCompression bool                       // 协商 permessage-deflate 压缩
+	Subprotocols      []string                   // 支持的子协议, 按优先级排列
+	CheckOrigin       func(r *http.Request) bool // 校验握手请求的来源, 为空时仅允许同源请求
+	hub               any
+}
+
+// NewOptions 返回默认配置, 默认使用内存广播
+func NewOptions() *Options {
+	return &Options{
+		ReadLimit:        64 << 10,
+		SendBufferSize:   256,
+		WriteTimeout:     10 * time.Second,
+		PingInterval:     30 * time.Second,
+		PongTimeout:      60 * time.Second,
+		HandshakeTimeout: 10 * time.Second,
+		CloseTimeout:     5 * time.Second,
+	}
+}
+
+// UseHub 使用自定义广播, constructor 为返回 web.WebSocketHub 实现的构造函数, 参数由容器注入
+// 如 UseHub(websocket.NewRedisHub) 通过默认 Redis 客户端在多个实例间广播
+func (o *Options) UseHub(constructor any) *Options {
+	o.hub = constructor
+	return o
+}
+
+// Hub 返回自定义广播的构造函数, 未配置时返回 nil
+func (o *Options) Hub() any {
+	return o.hub
+}
@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// redisChannel Redis 广播频道
+const redisChannel = "websocket:hub"
+
+// redisEnvelope Redis 广播消息
+type redisEnvelope struct {
+	Group string          `json:"group"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// RedisHub 通过 Redis 发布订阅在多个实例间广播, 每个实例只向本实例的连接写出
+// 广播的消息经 Redis 回到包括发布者在内的全部实例
+type RedisHub struct {
+	*MemoryHub
+	client *redis.Client
+	logger *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRedisHub 创建 Redis 广播, 订阅随应用启动与停止
+func NewRedisHub(client *redis.Client, lc fx.Lifecycle, logger *zap.Logger) *RedisHub {
+	h := &RedisHub{
+		MemoryHub: NewMemoryHub(logger),
+		client:    client,
+		logger:    logger,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: h.start,
+		OnStop: func(ctx context.Context) error {
+			if h.cancel != nil {
+				h.cancel()
+			}
+			h.wg.Wait()
+			return nil
+		},
+	})
+	return h
+}
+
+// Broadcast 将消息发布到 Redis
+func (h *RedisHub) Broadcast(ctx context.Context, group string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(redisEnvelope{Group: group, Data: data})
+	if err != nil {
+		return err
+	}
+	return h.client.Publish(ctx, redisChannel, payload).Err()
+}
+
+// start 订阅广播频道, 订阅确认后返回
+func (h *RedisHub) start(ctx context.Context) error {
+	pubsub := h.client.Subscribe(ctx, redisChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var envelope redisEnvelope
+				if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+					h.logger.Warn("invalid websocket broadcast message", zap.Error(err))
+					continue
+				}
+				h.deliver(envelope.Group, envelope.Data)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,184 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	gws "github.com/gorilla/websocket"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/problem"
+	"github.com/xiaohangshu-dev/go-workit/pkg/webapp/web"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Server WebSocket 服务, 升级连接并跟踪全部连接, 应用停止时以 1001 关闭帧关闭连接并等待处理函数返回
+type Server struct {
+	opts     *Options
+	upgrader *gws.Upgrader
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	conns    map[*Conn]struct{}
+	stopping bool
+	wg       sync.WaitGroup
+}
+
+// NewServer 创建 WebSocket 服务
+func NewServer(opts *Options, lc fx.Lifecycle, logger *zap.Logger) *Server {
+	s := &Server{
+		opts: opts,
+		upgrader: &gws.Upgrader{
+			HandshakeTimeout:  opts.HandshakeTimeout,
+			Subprotocols:      opts.Subprotocols,
+			EnableCompression: opts.EnableCompression,
+			CheckOrigin:       opts.CheckOrigin,
+			Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+				http.Error(w, reason.Error(), status)
+			},
+		},
+		logger: logger,
+		conns:  make(map[*Conn]struct{}),
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: s.shutdown,
+	})
+	return s
+}
+
+// Handler 返回升级连接并调用 handler 的处理函数
+// 握手请求已经过鉴权、授权与限流中间件, 认证的用户可通过连接的 Principal 获取
+func (s *Server) Handler(handler web.WebSocketHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !gws.IsWebSocketUpgrade(c.Request) {
+			web.AbortWithError(c, problem.New(http.StatusUpgradeRequired, "websocket upgrade required"))
+			return
+		}
+
+		ws, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// 握手失败时 Upgrader 已写出错误响应
+			s.logger.Info("websocket handshake failed", zap.String("path", c.Request.URL.Path), zap.Error(err))
+			c.Abort()
+			return
+		}
+
+		conn := newConn(ws, c, principal(c), s.opts)
+		go conn.writeLoop()
+
+		if !s.track(conn) {
+			_ = conn.Close(gws.CloseServiceRestart, "server is shutting down")
+			return
+		}
+		defer s.untrack(conn)
+
+		if err := s.serve(handler, conn); err != nil {
+			s.logger.Error("websocket handler failed",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("conn", conn.ID()),
+				zap.Error(err))
+			_ = conn.Close(gws.CloseInternalServerErr, "internal error")
+			return
+		}
+		_ = conn.Close(gws.CloseNormalClosure, "")
+	}
+}
+
+// Connections 当前连接数
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.conns)
+}
+
+// serve 调用处理函数, 对端关闭、连接已关闭、读超时与消息过大均不视为处理函数的错误
+func (s *Server) serve(handler web.WebSocketHandler, conn *Conn) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("websocket handler panic: %v", r)
+		}
+	}()
+
+	err = handler(conn)
+	if err == nil || conn.Context().Err() != nil || isConnectionError(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) track(conn *Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopping {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrack(conn *Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+
+	s.wg.Done()
+}
+
+// shutdown 拒绝新连接, 关闭全部连接并等待处理函数返回
+func (s *Server) shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopping = true
+	conns := make([]*Conn, 0, len(s.conns))
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	if len(conns) > 0 {
+		s.logger.Info("closing websocket connections", zap.Int("count", len(conns)))
+	}
+	for _, conn := range conns {
+		go func(conn *Conn) {
+			_ = conn.Close(gws.CloseGoingAway, "server is shutting down")
+		}(conn)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// principal 握手请求认证的用户
+func principal(c *gin.Context) *web.ClaimsPrincipal {
+	if p, ok := web.ClaimsPrincipalFromContext(c.Request.Context()); ok {
+		return p
+	}
+	return nil
+}
+
+// isConnectionError 是否为连接本身的错误, 包括对端关闭与异常断开
+func isConnectionError(err error) bool {
+	var (
+		netErr   net.Error
+		closeErr *gws.CloseError
+	)
+	return errors.Is(err, web.ErrWebSocketClosed) || errors.Is(err, net.ErrClosed) || errors.Is(err, gws.ErrReadLimit) ||
+		errors.As(err, &closeErr) || (errors.As(err, &netErr) && netErr.Timeout())
+}